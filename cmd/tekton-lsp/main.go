@@ -2,16 +2,26 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 
 	"github.com/tektoncd/tekton-lsp/pkg/server"
+	"github.com/tektoncd/tekton-lsp/pkg/trace"
 	"go.lsp.dev/jsonrpc2"
 	"go.uber.org/zap"
 )
 
+var (
+	listen = flag.String("listen", "", "listen on this TCP address instead of using stdio (e.g. -listen=:4389)")
+	socket = flag.String("socket", "", "listen on this Unix domain socket instead of using stdio")
+	trc    = flag.String("trace", "", "JSON-RPC wire trace level: off, messages, or verbose (defaults to $TEKTON_LSP_TRACE)")
+)
+
 func main() {
+	flag.Parse()
+
 	// Initialize logger
 	logger, err := zap.NewDevelopment()
 	if err != nil {
@@ -20,27 +30,43 @@ func main() {
 	}
 	defer logger.Sync()
 
+	traceLevel := trace.ParseLevel(os.Getenv("TEKTON_LSP_TRACE"))
+	if *trc != "" {
+		traceLevel = trace.ParseLevel(*trc)
+	}
+
 	// Create LSP server
-	srv := server.NewServer(logger)
-
-	// Create JSON-RPC 2.0 stream using stdio
-	// Combine stdin and stdout into a single ReadWriteCloser
-	stream := jsonrpc2.NewStream(struct {
-		io.Reader
-		io.Writer
-		io.Closer
-	}{
-		Reader: os.Stdin,
-		Writer: os.Stdout,
-		Closer: os.Stdin,
-	})
+	srv := server.NewServer(logger, traceLevel)
 
 	ctx := context.Background()
 
 	logger.Info("Tekton LSP server starting")
 
-	// Serve requests
-	if err := srv.Serve(ctx, stream); err != nil {
+	switch {
+	case *listen != "" && *socket != "":
+		fmt.Fprintln(os.Stderr, "cannot use both -listen and -socket")
+		os.Exit(1)
+	case *listen != "":
+		err = srv.ListenAndServe(ctx, "tcp", *listen)
+	case *socket != "":
+		err = srv.ListenAndServe(ctx, "unix", *socket)
+	default:
+		// Default to stdio: combine stdin and stdout into a single
+		// ReadWriteCloser and speak JSON-RPC 2.0 over it.
+		rwc, tracer := srv.TraceStream(struct {
+			io.Reader
+			io.Writer
+			io.Closer
+		}{
+			Reader: os.Stdin,
+			Writer: os.Stdout,
+			Closer: os.Stdin,
+		})
+		stream := jsonrpc2.NewStream(rwc)
+		err = srv.Serve(ctx, stream, tracer)
+	}
+
+	if err != nil {
 		logger.Error("server error", zap.Error(err))
 		os.Exit(1)
 	}
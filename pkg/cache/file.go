@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"go.lsp.dev/protocol"
+)
+
+// ParsedFile is the result of parsing a single open text document. It is
+// immutable once built: any edit produces a brand new ParsedFile (and a new
+// Snapshot to hold it) rather than mutating this one in place.
+type ParsedFile struct {
+	URI        protocol.DocumentURI
+	LanguageID string
+	Version    int32
+	Text       string
+
+	// AST is the parsed YAML document tree, or nil if ParseErr is set.
+	AST *ast.File
+	// Kind is the detected Tekton resource kind, or KindUnknown for plain
+	// YAML or documents that failed to parse.
+	Kind ResourceKind
+	// Symbols holds one entry per referenceable resource (Task,
+	// ClusterTask, Pipeline, StepAction) declared anywhere in this file,
+	// including additional YAML documents beyond the first.
+	Symbols []Symbol
+	// Refs holds one entry per taskRef/pipelineRef found anywhere in this
+	// file.
+	Refs []Ref
+	// ParseErr holds the error from the last parse attempt, if any.
+	ParseErr error
+}
+
+// parseFile parses the given document text into a ParsedFile, detecting its
+// Tekton resource kind from the first YAML document's apiVersion/kind.
+func parseFile(uri protocol.DocumentURI, languageID string, version int32, text string) *ParsedFile {
+	pf := &ParsedFile{
+		URI:        uri,
+		LanguageID: languageID,
+		Version:    version,
+		Text:       text,
+	}
+
+	file, err := parseYAML(text)
+	if err != nil {
+		pf.ParseErr = err
+		return pf
+	}
+	pf.AST = file
+
+	if len(file.Docs) > 0 {
+		var tm typeMeta
+		if err := yaml.NodeToValue(file.Docs[0].Body, &tm); err == nil {
+			pf.Kind = tm.resourceKind()
+		}
+	}
+	pf.Symbols = scanSymbols(uri, file)
+	pf.Refs = FindRefs(file)
+
+	return pf
+}
+
+// parseYAML parses a document's raw text into a YAML AST.
+func parseYAML(text string) (*ast.File, error) {
+	return parser.ParseBytes([]byte(text), parser.ParseComments)
+}
@@ -0,0 +1,57 @@
+package cache
+
+// ResourceKind identifies the Tekton object kind a YAML document describes,
+// as read from its top-level apiVersion/kind fields.
+type ResourceKind string
+
+const (
+	KindTask        ResourceKind = "Task"
+	KindClusterTask ResourceKind = "ClusterTask"
+	KindPipeline    ResourceKind = "Pipeline"
+	KindPipelineRun ResourceKind = "PipelineRun"
+	KindTaskRun     ResourceKind = "TaskRun"
+	KindStepAction  ResourceKind = "StepAction"
+
+	// Tekton Triggers resources. These aren't taskRef/pipelineRef
+	// referenceable, but still need their own capability handler.
+	KindTriggerTemplate       ResourceKind = "TriggerTemplate"
+	KindTriggerBinding        ResourceKind = "TriggerBinding"
+	KindClusterTriggerBinding ResourceKind = "ClusterTriggerBinding"
+	KindEventListener         ResourceKind = "EventListener"
+
+	// KindUnknown covers plain YAML or any apiVersion/kind pair we don't
+	// recognize yet.
+	KindUnknown ResourceKind = ""
+)
+
+// typeMeta mirrors the apiVersion/kind fields every Kubernetes-style
+// manifest carries, used only to classify a document.
+type typeMeta struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+}
+
+// RefKindsPointingHere returns the taskRef/pipelineRef kinds that can
+// resolve to a resource of kind k.
+func (k ResourceKind) RefKindsPointingHere() []RefKind {
+	switch k {
+	case KindTask, KindClusterTask:
+		return []RefKind{RefTaskRef}
+	case KindPipeline:
+		return []RefKind{RefPipelineRef}
+	default:
+		return nil
+	}
+}
+
+func (t typeMeta) resourceKind() ResourceKind {
+	switch t.Kind {
+	case string(KindTask), string(KindClusterTask), string(KindPipeline),
+		string(KindPipelineRun), string(KindTaskRun), string(KindStepAction),
+		string(KindTriggerTemplate), string(KindTriggerBinding),
+		string(KindClusterTriggerBinding), string(KindEventListener):
+		return ResourceKind(t.Kind)
+	default:
+		return KindUnknown
+	}
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"github.com/goccy/go-yaml/ast"
+	"go.lsp.dev/protocol"
+)
+
+// RefKind identifies which Tekton reference field a Ref came from.
+type RefKind string
+
+const (
+	RefTaskRef     RefKind = "taskRef"
+	RefPipelineRef RefKind = "pipelineRef"
+)
+
+// ReferentKinds maps a RefKind to the Symbol kind(s) it can resolve to.
+// taskRef may point at a Task or a ClusterTask; pipelineRef only ever points
+// at a Pipeline.
+func (k RefKind) ReferentKinds() []ResourceKind {
+	switch k {
+	case RefTaskRef:
+		return []ResourceKind{KindTask, KindClusterTask}
+	case RefPipelineRef:
+		return []ResourceKind{KindPipeline}
+	default:
+		return nil
+	}
+}
+
+// Ref is one `taskRef.name:`/`pipelineRef.name:` occurrence found while
+// walking a document's YAML AST.
+type Ref struct {
+	Kind      RefKind
+	Name      string
+	NameRange protocol.Range
+}
+
+// FindRefs returns every taskRef/pipelineRef name found anywhere in file.
+func FindRefs(file *ast.File) []Ref {
+	if file == nil {
+		return nil
+	}
+	var refs []Ref
+	for _, doc := range file.Docs {
+		walkRefs(doc.Body, &refs)
+	}
+	return refs
+}
+
+// RefAt returns the taskRef/pipelineRef whose name value contains pos, if
+// any.
+func RefAt(file *ast.File, pos protocol.Position) (Ref, bool) {
+	for _, ref := range FindRefs(file) {
+		if contains(ref.NameRange, pos) {
+			return ref, true
+		}
+	}
+	return Ref{}, false
+}
+
+func contains(r protocol.Range, pos protocol.Position) bool {
+	if pos.Line < r.Start.Line || pos.Line > r.End.Line {
+		return false
+	}
+	if pos.Line == r.Start.Line && pos.Character < r.Start.Character {
+		return false
+	}
+	if pos.Line == r.End.Line && pos.Character > r.End.Character {
+		return false
+	}
+	return true
+}
+
+// walkRefs recursively visits node looking for `taskRef:`/`pipelineRef:`
+// mappings that carry a `name:` scalar, appending a Ref for each one found,
+// then keeps descending so refs nested in sequences (spec.tasks[*].taskRef)
+// are all discovered.
+func walkRefs(node ast.Node, refs *[]Ref) {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		for _, mv := range n.Values {
+			walkMappingValue(mv, refs)
+		}
+	case *ast.MappingValueNode:
+		walkMappingValue(n, refs)
+	case *ast.SequenceNode:
+		for _, item := range n.Values {
+			walkRefs(item, refs)
+		}
+	}
+}
+
+func walkMappingValue(mv *ast.MappingValueNode, refs *[]Ref) {
+	if mv == nil || mv.Key == nil {
+		return
+	}
+
+	key := mv.Key.String()
+	if key == string(RefTaskRef) || key == string(RefPipelineRef) {
+		if name, rng, ok := StringFieldAt(mv.Value, "$.name"); ok {
+			*refs = append(*refs, Ref{
+				Kind:      RefKind(key),
+				Name:      name,
+				NameRange: rng,
+			})
+		}
+	}
+
+	walkRefs(mv.Value, refs)
+}
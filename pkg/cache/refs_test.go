@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"testing"
+
+	"go.lsp.dev/protocol"
+)
+
+const pipelineYAML = `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: my-pipeline
+spec:
+  tasks:
+    - name: build
+      taskRef:
+        name: build-task
+    - name: deploy
+      taskRef:
+        name: deploy-task
+      runAfter:
+        - build
+    - name: run
+      pipelineRef:
+        name: sub-pipeline
+`
+
+func TestFindRefs(t *testing.T) {
+	pf := parseFile("file:///pipeline.yaml", "yaml", 1, pipelineYAML)
+	if pf.ParseErr != nil {
+		t.Fatalf("parseFile() error = %v", pf.ParseErr)
+	}
+
+	refs := FindRefs(pf.AST)
+	want := map[string]RefKind{
+		"build-task":   RefTaskRef,
+		"deploy-task":  RefTaskRef,
+		"sub-pipeline": RefPipelineRef,
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("FindRefs() returned %d refs, want %d: %+v", len(refs), len(want), refs)
+	}
+	for _, ref := range refs {
+		kind, ok := want[ref.Name]
+		if !ok {
+			t.Errorf("unexpected ref %q", ref.Name)
+			continue
+		}
+		if ref.Kind != kind {
+			t.Errorf("ref %q kind = %v, want %v", ref.Name, ref.Kind, kind)
+		}
+	}
+}
+
+func TestRefAt(t *testing.T) {
+	pf := parseFile("file:///pipeline.yaml", "yaml", 1, pipelineYAML)
+	if pf.ParseErr != nil {
+		t.Fatalf("parseFile() error = %v", pf.ParseErr)
+	}
+
+	ref, ok := RefAt(pf.AST, protocol.Position{Line: 8, Character: 14})
+	if !ok {
+		t.Fatal("RefAt() found no ref at build-task's position")
+	}
+	if ref.Name != "build-task" || ref.Kind != RefTaskRef {
+		t.Errorf("RefAt() = %+v, want name=build-task kind=taskRef", ref)
+	}
+
+	if _, ok := RefAt(pf.AST, protocol.Position{Line: 0, Character: 0}); ok {
+		t.Error("RefAt() found a ref at a position with no taskRef/pipelineRef")
+	}
+}
+
+func TestReferentKinds(t *testing.T) {
+	tests := []struct {
+		kind RefKind
+		want []ResourceKind
+	}{
+		{RefTaskRef, []ResourceKind{KindTask, KindClusterTask}},
+		{RefPipelineRef, []ResourceKind{KindPipeline}},
+		{RefKind("bogus"), nil},
+	}
+	for _, tt := range tests {
+		got := tt.kind.ReferentKinds()
+		if len(got) != len(tt.want) {
+			t.Errorf("%v.ReferentKinds() = %v, want %v", tt.kind, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%v.ReferentKinds() = %v, want %v", tt.kind, got, tt.want)
+				break
+			}
+		}
+	}
+}
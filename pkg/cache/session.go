@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+
+	"go.lsp.dev/protocol"
+	"go.uber.org/zap"
+)
+
+// Session owns the Views for every workspace folder the client has told us
+// about, plus a default View for documents that don't fall under any
+// folder. It is the entry point pkg/server wires the DidOpen/DidChange/
+// DidSave/DidClose notifications through.
+type Session struct {
+	logger   *zap.Logger
+	diagnose DiagnoseFunc
+
+	mu          sync.Mutex
+	views       []*View
+	defaultView *View
+}
+
+// NewSession creates an empty Session. diagnose is invoked for every new
+// snapshot produced by any View belonging to this session.
+func NewSession(logger *zap.Logger, diagnose DiagnoseFunc) *Session {
+	s := &Session{
+		logger:   logger,
+		diagnose: diagnose,
+	}
+	s.defaultView = NewView(logger, "", diagnose)
+	return s
+}
+
+// AddFolder registers a workspace folder, creating a View for it if one
+// doesn't already exist.
+func (s *Session) AddFolder(folder protocol.DocumentURI) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.views {
+		if v.folder == folder {
+			return
+		}
+	}
+	s.views = append(s.views, NewView(s.logger, folder, s.diagnose))
+}
+
+// RemoveFolder drops the View for folder. Its snapshot is discarded.
+func (s *Session) RemoveFolder(folder protocol.DocumentURI) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, v := range s.views {
+		if v.folder == folder {
+			s.views = append(s.views[:i], s.views[i+1:]...)
+			return
+		}
+	}
+}
+
+// ViewOf returns the View whose folder most closely contains uri, falling
+// back to the session's default View if uri isn't under any known folder.
+func (s *Session) ViewOf(uri protocol.DocumentURI) *View {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var best *View
+	for _, v := range s.views {
+		if folderContains(v.folder, uri) {
+			if best == nil || len(v.folder) > len(best.folder) {
+				best = v
+			}
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return s.defaultView
+}
+
+// folderContains reports whether uri names a document under folder: either
+// folder itself, or a path strictly below it. A plain strings.HasPrefix
+// would also match a sibling folder whose name happens to start with
+// folder's (e.g. folder "file:///repo/project" matching uri under
+// "file:///repo/project-old/"), so this requires a "/" boundary right
+// after folder before treating it as a match.
+func folderContains(folder, uri protocol.DocumentURI) bool {
+	f, u := string(folder), string(uri)
+	if u == f {
+		return true
+	}
+	if !strings.HasSuffix(f, "/") {
+		f += "/"
+	}
+	return strings.HasPrefix(u, f)
+}
+
+// Views returns every View currently tracked by the session, including the
+// default one.
+func (s *Session) Views() []*View {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	views := make([]*View, 0, len(s.views)+1)
+	views = append(views, s.views...)
+	views = append(views, s.defaultView)
+	return views
+}
+
+func (s *Session) DidOpen(uri protocol.DocumentURI, languageID string, version int32, text string) {
+	s.ViewOf(uri).DidOpen(uri, languageID, version, text)
+}
+
+func (s *Session) DidChange(uri protocol.DocumentURI, version int32, changes []protocol.TextDocumentContentChangeEvent) {
+	s.ViewOf(uri).DidChange(uri, version, changes)
+}
+
+func (s *Session) DidSave(uri protocol.DocumentURI) {
+	s.ViewOf(uri).DidSave(uri)
+}
+
+func (s *Session) DidClose(uri protocol.DocumentURI) {
+	s.ViewOf(uri).DidClose(uri)
+}
+
+// DidChangeWatchedFiles refreshes the workspace index of every view for
+// files that changed on disk, as reported by the client in response to the
+// watchers the server registers during initialization.
+func (s *Session) DidChangeWatchedFiles(changes []protocol.FileEvent) {
+	for _, change := range changes {
+		for _, v := range s.Views() {
+			v.DidChangeWatchedFile(change.URI, change.Type)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"testing"
+
+	"go.lsp.dev/protocol"
+)
+
+func TestFolderContains(t *testing.T) {
+	tests := []struct {
+		name   string
+		folder protocol.DocumentURI
+		uri    protocol.DocumentURI
+		want   bool
+	}{
+		{"exact match", "file:///repo/project", "file:///repo/project", true},
+		{"nested document", "file:///repo/project", "file:///repo/project/foo.yaml", true},
+		{"sibling folder with shared prefix", "file:///repo/project", "file:///repo/project-old/foo.yaml", false},
+		{"unrelated folder", "file:///repo/project", "file:///repo/other/foo.yaml", false},
+		{"folder already has trailing slash", "file:///repo/project/", "file:///repo/project/foo.yaml", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := folderContains(tt.folder, tt.uri); got != tt.want {
+				t.Errorf("folderContains(%q, %q) = %v, want %v", tt.folder, tt.uri, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+
+	"go.lsp.dev/protocol"
+)
+
+// Snapshot is an immutable view of all open documents in a View at a point
+// in time. A new Snapshot is produced for every DidOpen/DidChange/DidSave/
+// DidClose; nothing in a published Snapshot is ever mutated afterwards, so
+// it is safe to read concurrently without locking.
+type Snapshot struct {
+	view *View
+
+	// generation increases by one for every snapshot cloned from a view.
+	// No code reads it; it only tells snapshots apart when debugging.
+	generation uint64
+
+	files map[protocol.DocumentURI]*ParsedFile
+
+	// cancel cancels ctx, the context passed to analyses (diagnostics,
+	// cross-file resolution) running against this snapshot. It is called
+	// as soon as a newer snapshot replaces this one, so stale work can
+	// stop early instead of racing the result of the new snapshot.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// clone returns a new Snapshot that shares every file with s except for the
+// ones overridden by apply, which is called with a copy of s's file map.
+func (s *Snapshot) clone(ctx context.Context, apply func(files map[protocol.DocumentURI]*ParsedFile)) *Snapshot {
+	files := make(map[protocol.DocumentURI]*ParsedFile, len(s.files))
+	for uri, pf := range s.files {
+		files[uri] = pf
+	}
+	apply(files)
+
+	childCtx, cancel := context.WithCancel(ctx)
+	return &Snapshot{
+		view:       s.view,
+		generation: s.generation + 1,
+		files:      files,
+		ctx:        childCtx,
+		cancel:     cancel,
+	}
+}
+
+// View returns the View this snapshot belongs to.
+func (s *Snapshot) View() *View {
+	return s.view
+}
+
+// File returns the parsed file for uri and whether it is currently open.
+func (s *Snapshot) File(uri protocol.DocumentURI) (*ParsedFile, bool) {
+	pf, ok := s.files[uri]
+	return pf, ok
+}
+
+// Files returns every open file held by this snapshot. The caller must treat
+// the returned slice and its contents as read-only.
+func (s *Snapshot) Files() []*ParsedFile {
+	files := make([]*ParsedFile, 0, len(s.files))
+	for _, pf := range s.files {
+		files = append(files, pf)
+	}
+	return files
+}
+
+// FindByKind returns every open file of the given resource kind.
+func (s *Snapshot) FindByKind(kind ResourceKind) []*ParsedFile {
+	var found []*ParsedFile
+	for _, pf := range s.files {
+		if pf.Kind == kind {
+			found = append(found, pf)
+		}
+	}
+	return found
+}
@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"go.lsp.dev/protocol"
+)
+
+// Symbol is one Tekton resource definition (a Task, ClusterTask, Pipeline or
+// StepAction manifest) discovered either in an open document or by walking
+// the workspace on disk. taskRef/pipelineRef completion, definition,
+// references and rename are all built on top of these.
+type Symbol struct {
+	Name string
+	Kind ResourceKind
+	URI  protocol.DocumentURI
+	// NameRange is the location of the metadata.name scalar itself, used
+	// as the Definition target and the Rename anchor.
+	NameRange protocol.Range
+	// Text is the source of just this symbol's own YAML document, not the
+	// whole file it was found in. A file can hold several `---`-separated
+	// Tekton manifests, so completion's "embed the missing manifest"
+	// quick fix needs this to splice in only the referenced resource
+	// rather than every document sharing its file.
+	Text string
+}
+
+// referenceableKinds are the resource kinds indexed as Symbols: the Task,
+// ClusterTask and Pipeline kinds a taskRef or pipelineRef can point at (see
+// RefKind.ReferentKinds), plus StepAction.
+var referenceableKinds = map[ResourceKind]bool{
+	KindTask:        true,
+	KindClusterTask: true,
+	KindPipeline:    true,
+	KindStepAction:  true,
+}
+
+// scanSymbols extracts one Symbol per YAML document in file whose kind is
+// referenceable and that declares a metadata.name.
+func scanSymbols(uri protocol.DocumentURI, file *ast.File) []Symbol {
+	if file == nil {
+		return nil
+	}
+
+	var symbols []Symbol
+	for _, doc := range file.Docs {
+		if doc.Body == nil {
+			continue
+		}
+
+		var tm typeMeta
+		if err := yaml.NodeToValue(doc.Body, &tm); err != nil {
+			continue
+		}
+		kind := tm.resourceKind()
+		if !referenceableKinds[kind] {
+			continue
+		}
+
+		name, rng, ok := StringFieldAt(doc.Body, "$.metadata.name")
+		if !ok {
+			continue
+		}
+
+		symbols = append(symbols, Symbol{
+			Name:      name,
+			Kind:      kind,
+			URI:       uri,
+			NameRange: rng,
+			Text:      doc.Body.String(),
+		})
+	}
+	return symbols
+}
+
+// StringFieldAt resolves a YAML path (e.g. "$.metadata.name") against root
+// and returns its scalar string value and source range.
+func StringFieldAt(root ast.Node, path string) (string, protocol.Range, bool) {
+	p, err := yaml.PathString(path)
+	if err != nil {
+		return "", protocol.Range{}, false
+	}
+
+	node, err := p.FilterNode(root)
+	if err != nil || node == nil {
+		return "", protocol.Range{}, false
+	}
+
+	str, ok := node.(*ast.StringNode)
+	if !ok {
+		return "", protocol.Range{}, false
+	}
+
+	return str.Value, RangeOf(node, str.Value), true
+}
+
+// SymbolAt returns the Symbol whose metadata.name declaration contains pos,
+// if any.
+func SymbolAt(pf *ParsedFile, pos protocol.Position) (Symbol, bool) {
+	for _, sym := range pf.Symbols {
+		if contains(sym.NameRange, pos) {
+			return sym, true
+		}
+	}
+	return Symbol{}, false
+}
+
+// RangeOf returns the LSP range covering a scalar node's value, converting
+// goccy/go-yaml's 1-based line/column token position to 0-based LSP
+// positions. value may span multiple lines (a block scalar like `script:
+// |`), in which case the end position lands on the line the value's last
+// line ends on rather than staying on the start line.
+func RangeOf(node ast.Node, value string) protocol.Range {
+	tok := node.GetToken()
+	if tok == nil {
+		return protocol.Range{}
+	}
+
+	line := uint32(0)
+	if tok.Position.Line > 0 {
+		line = uint32(tok.Position.Line - 1)
+	}
+	col := uint32(0)
+	if tok.Position.Column > 0 {
+		col = uint32(tok.Position.Column - 1)
+	}
+
+	start := protocol.Position{Line: line, Character: col}
+
+	lines := strings.Split(value, "\n")
+	end := start
+	if len(lines) > 1 {
+		end.Line += uint32(len(lines) - 1)
+		end.Character = uint32(len([]rune(lines[len(lines)-1])))
+	} else {
+		end.Character += uint32(len([]rune(value)))
+	}
+	return protocol.Range{Start: start, End: end}
+}
+
+// RangeOfIndex returns the LSP range covering value[index:index+length], a
+// substring of a scalar node's full value (for example, one matched
+// $(params.x)-style reference inside a longer multi-line script block),
+// anchored at the node's own source position the same way RangeOf is.
+//
+// Lines after the first are assumed to share the node's own indentation,
+// which holds for the one multi-line case this is meant to support: a
+// block scalar's dedented value, where goccy/go-yaml strips every
+// continuation line back to the same column the block started at.
+func RangeOfIndex(node ast.Node, value string, index, length int) protocol.Range {
+	tok := node.GetToken()
+	if tok == nil {
+		return protocol.Range{}
+	}
+
+	line := uint32(0)
+	if tok.Position.Line > 0 {
+		line = uint32(tok.Position.Line - 1)
+	}
+	indent := uint32(0)
+	if tok.Position.Column > 0 {
+		indent = uint32(tok.Position.Column - 1)
+	}
+	col := indent
+
+	before := strings.Split(value[:index], "\n")
+	if n := len(before) - 1; n > 0 {
+		line += uint32(n)
+		col = indent + uint32(len([]rune(before[n])))
+	} else {
+		col += uint32(len([]rune(before[0])))
+	}
+	start := protocol.Position{Line: line, Character: col}
+
+	match := strings.Split(value[index:index+length], "\n")
+	end := start
+	if n := len(match) - 1; n > 0 {
+		end.Line += uint32(n)
+		end.Character = indent + uint32(len([]rune(match[n])))
+	} else {
+		end.Character += uint32(len([]rune(match[0])))
+	}
+	return protocol.Range{Start: start, End: end}
+}
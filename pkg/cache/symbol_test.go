@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+
+	"go.lsp.dev/protocol"
+)
+
+const multiDocTasksYAML = `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  steps:
+    - name: build
+      image: golang
+---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: test
+spec:
+  steps:
+    - name: test
+      image: golang
+`
+
+func TestScanSymbols(t *testing.T) {
+	pf := parseFile("file:///tasks.yaml", "yaml", 1, multiDocTasksYAML)
+	if pf.ParseErr != nil {
+		t.Fatalf("parseFile() error = %v", pf.ParseErr)
+	}
+
+	if len(pf.Symbols) != 2 {
+		t.Fatalf("scanSymbols() found %d symbols, want 2: %+v", len(pf.Symbols), pf.Symbols)
+	}
+
+	for i, name := range []string{"build", "test"} {
+		sym := pf.Symbols[i]
+		if sym.Name != name {
+			t.Errorf("Symbols[%d].Name = %q, want %q", i, sym.Name, name)
+		}
+		if sym.Kind != KindTask {
+			t.Errorf("Symbols[%d].Kind = %v, want %v", i, sym.Kind, KindTask)
+		}
+		if !strings.Contains(sym.Text, "name: "+name) {
+			t.Errorf("Symbols[%d].Text doesn't contain its own document:\n%s", i, sym.Text)
+		}
+	}
+
+	// Each symbol's Text must hold only its own document, not the whole
+	// file: the second Task's manifest shouldn't leak into the first's.
+	if strings.Contains(pf.Symbols[0].Text, "name: test") {
+		t.Errorf("Symbols[0].Text leaked the second document:\n%s", pf.Symbols[0].Text)
+	}
+}
+
+func TestSymbolAt(t *testing.T) {
+	pf := parseFile("file:///tasks.yaml", "yaml", 1, multiDocTasksYAML)
+	if pf.ParseErr != nil {
+		t.Fatalf("parseFile() error = %v", pf.ParseErr)
+	}
+
+	sym, ok := SymbolAt(pf, pf.Symbols[0].NameRange.Start)
+	if !ok || sym.Name != "build" {
+		t.Errorf("SymbolAt(first symbol's own range) = %+v, %v, want build", sym, ok)
+	}
+
+	if _, ok := SymbolAt(pf, protocol.Position{Line: 0, Character: 0}); ok {
+		t.Error("SymbolAt() found a symbol at apiVersion's position")
+	}
+}
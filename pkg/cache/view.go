@@ -0,0 +1,246 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"go.lsp.dev/protocol"
+	"go.uber.org/zap"
+)
+
+// DiagnoseFunc runs whatever analyses the server wants against a snapshot
+// (schema validation, cross-file reference resolution, ...) and publishes
+// the results. It is called in its own goroutine after every snapshot
+// change, with a context that is cancelled as soon as a newer snapshot
+// supersedes the one passed in.
+type DiagnoseFunc func(ctx context.Context, snap *Snapshot)
+
+// View represents one workspace folder. It owns the current Snapshot and is
+// responsible for producing the next one in response to text-sync events,
+// following the same snapshot-per-change model gopls uses for Go packages.
+type View struct {
+	logger    *zap.Logger
+	folder    protocol.DocumentURI
+	diagnose  DiagnoseFunc
+	workspace *WorkspaceIndex
+
+	mu   sync.Mutex
+	snap *Snapshot
+}
+
+// NewView creates a View rooted at folder with an empty initial snapshot,
+// scanning the folder on disk to seed its workspace-wide symbol index.
+func NewView(logger *zap.Logger, folder protocol.DocumentURI, diagnose DiagnoseFunc) *View {
+	v := &View{
+		logger:    logger,
+		folder:    folder,
+		diagnose:  diagnose,
+		workspace: NewWorkspaceIndex(logger, folder),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	v.snap = &Snapshot{
+		view:   v,
+		files:  make(map[protocol.DocumentURI]*ParsedFile),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	return v
+}
+
+// Lookup returns every symbol of the given kind and name visible to this
+// view, preferring the in-memory copy from an open document over the
+// on-disk one the workspace index holds for the same file.
+func (v *View) Lookup(kind ResourceKind, name string) []Symbol {
+	var found []Symbol
+	seen := make(map[protocol.DocumentURI]bool)
+
+	for _, pf := range v.Snapshot().Files() {
+		seen[pf.URI] = true
+		for _, sym := range pf.Symbols {
+			if sym.Kind == kind && sym.Name == name {
+				found = append(found, sym)
+			}
+		}
+	}
+
+	for _, sym := range v.workspace.Lookup(kind, name) {
+		if !seen[sym.URI] {
+			found = append(found, sym)
+		}
+	}
+	return found
+}
+
+// Symbols returns every known symbol of the given kind, merging open
+// documents with the on-disk workspace index the same way Lookup does.
+func (v *View) Symbols(kind ResourceKind) []Symbol {
+	var found []Symbol
+	seen := make(map[protocol.DocumentURI]bool)
+
+	for _, pf := range v.Snapshot().Files() {
+		seen[pf.URI] = true
+		for _, sym := range pf.Symbols {
+			if sym.Kind == kind {
+				found = append(found, sym)
+			}
+		}
+	}
+
+	for _, sym := range v.workspace.All(kind) {
+		if !seen[sym.URI] {
+			found = append(found, sym)
+		}
+	}
+	return found
+}
+
+// RefsByName returns every taskRef/pipelineRef occurrence of the given kind
+// and name visible to this view, preferring the in-memory copy from an open
+// document over the on-disk one the workspace index holds for the same
+// file.
+func (v *View) RefsByName(kind RefKind, name string) []RefOccurrence {
+	var found []RefOccurrence
+	seen := make(map[protocol.DocumentURI]bool)
+
+	for _, pf := range v.Snapshot().Files() {
+		seen[pf.URI] = true
+		for _, ref := range pf.Refs {
+			if ref.Kind == kind && ref.Name == name {
+				found = append(found, RefOccurrence{URI: pf.URI, Ref: ref})
+			}
+		}
+	}
+
+	for _, occ := range v.workspace.RefsByName(kind, name) {
+		if !seen[occ.URI] {
+			found = append(found, occ)
+		}
+	}
+	return found
+}
+
+// DidChangeWatchedFile refreshes the workspace index for a single file that
+// changed on disk outside of an open editor buffer.
+func (v *View) DidChangeWatchedFile(docURI protocol.DocumentURI, changeType protocol.FileChangeType) {
+	switch changeType {
+	case protocol.FileChangeTypeDeleted:
+		v.workspace.Remove(docURI)
+	default: // Created or Changed
+		v.workspace.Update(docURI)
+	}
+}
+
+// Snapshot returns the current snapshot.
+func (v *View) Snapshot() *Snapshot {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.snap
+}
+
+// DidOpen records a newly opened document and schedules diagnostics.
+func (v *View) DidOpen(uri protocol.DocumentURI, languageID string, version int32, text string) {
+	v.update(func(files map[protocol.DocumentURI]*ParsedFile) {
+		files[uri] = parseFile(uri, languageID, version, text)
+	})
+}
+
+// DidChange applies a sequence of full-document or incremental content
+// changes (as sent for TextDocumentSyncKindIncremental) to an already-open
+// document and reparses the result.
+func (v *View) DidChange(uri protocol.DocumentURI, version int32, changes []protocol.TextDocumentContentChangeEvent) {
+	v.update(func(files map[protocol.DocumentURI]*ParsedFile) {
+		languageID := ""
+		text := ""
+		if pf, ok := files[uri]; ok {
+			languageID = pf.LanguageID
+			text = pf.Text
+		}
+
+		for _, change := range changes {
+			text = applyContentChange(text, change)
+		}
+
+		files[uri] = parseFile(uri, languageID, version, text)
+	})
+}
+
+// applyContentChange applies a single TextDocumentContentChangeEvent to
+// text. A change with no Range replaces the whole document, as the LSP spec
+// requires clients to send when announcing TextDocumentSyncKindFull; a
+// change with a Range splices change.Text into that range.
+//
+// Positions are interpreted as UTF-16 code unit offsets per the LSP spec.
+// Tekton YAML manifests are effectively always ASCII, so we approximate
+// UTF-16 offsets with rune offsets; this only diverges for text containing
+// characters outside the basic multilingual plane, which we don't expect to
+// see in practice.
+func applyContentChange(text string, change protocol.TextDocumentContentChangeEvent) string {
+	if change.Range == (protocol.Range{}) {
+		return change.Text
+	}
+
+	start := offsetAt(text, change.Range.Start)
+	end := offsetAt(text, change.Range.End)
+	return text[:start] + change.Text + text[end:]
+}
+
+// offsetAt converts a 0-based line/character position into a byte offset
+// into text.
+func offsetAt(text string, pos protocol.Position) int {
+	line := 0
+	offset := 0
+	for line < int(pos.Line) {
+		idx := strings.IndexByte(text[offset:], '\n')
+		if idx < 0 {
+			return len(text)
+		}
+		offset += idx + 1
+		line++
+	}
+
+	rest := text[offset:]
+	if idx := strings.IndexByte(rest, '\n'); idx >= 0 {
+		rest = rest[:idx]
+	}
+
+	runes := []rune(rest)
+	col := int(pos.Character)
+	if col > len(runes) {
+		col = len(runes)
+	}
+
+	return offset + len(string(runes[:col]))
+}
+
+// DidSave notes that a document was saved. It does not change the cached
+// text (the client only sends text on save if it chooses to), but still
+// triggers a diagnostics pass since some checks may depend on on-disk state
+// of other files (e.g. cross-file taskRef resolution).
+func (v *View) DidSave(uri protocol.DocumentURI) {
+	v.update(func(files map[protocol.DocumentURI]*ParsedFile) {})
+}
+
+// DidClose drops a document from the cache.
+func (v *View) DidClose(uri protocol.DocumentURI) {
+	v.update(func(files map[protocol.DocumentURI]*ParsedFile) {
+		delete(files, uri)
+	})
+}
+
+// update builds the next snapshot from the current one, cancels any
+// analyses still running against the previous snapshot, installs the new
+// snapshot, and kicks off a fresh diagnostics pass for it.
+func (v *View) update(apply func(files map[protocol.DocumentURI]*ParsedFile)) {
+	v.mu.Lock()
+	prev := v.snap
+	next := prev.clone(context.Background(), apply)
+	v.snap = next
+	v.mu.Unlock()
+
+	prev.cancel()
+
+	if v.diagnose != nil {
+		go v.diagnose(next.ctx, next)
+	}
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"testing"
+
+	"go.lsp.dev/protocol"
+)
+
+func TestApplyContentChangeFull(t *testing.T) {
+	change := protocol.TextDocumentContentChangeEvent{Text: "new text"}
+	got := applyContentChange("old text", change)
+	if got != "new text" {
+		t.Errorf("applyContentChange() = %q, want %q", got, "new text")
+	}
+}
+
+func TestApplyContentChangeRange(t *testing.T) {
+	text := "line one\nline two\nline three"
+	change := protocol.TextDocumentContentChangeEvent{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: 1, Character: 5},
+			End:   protocol.Position{Line: 1, Character: 8},
+		},
+		Text: "TWO",
+	}
+	got := applyContentChange(text, change)
+	want := "line one\nline TWO\nline three"
+	if got != want {
+		t.Errorf("applyContentChange() = %q, want %q", got, want)
+	}
+}
+
+func TestOffsetAt(t *testing.T) {
+	text := "abc\ndefgh\nij"
+	tests := []struct {
+		name string
+		pos  protocol.Position
+		want int
+	}{
+		{"start of text", protocol.Position{Line: 0, Character: 0}, 0},
+		{"mid first line", protocol.Position{Line: 0, Character: 2}, 2},
+		{"start of second line", protocol.Position{Line: 1, Character: 0}, 4},
+		{"mid second line", protocol.Position{Line: 1, Character: 3}, 7},
+		{"character past end of line clamps", protocol.Position{Line: 1, Character: 99}, 9},
+		{"line past end of text clamps", protocol.Position{Line: 99, Character: 0}, len(text)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := offsetAt(text, tt.pos); got != tt.want {
+				t.Errorf("offsetAt(%q, %+v) = %d, want %d", text, tt.pos, got, tt.want)
+			}
+		})
+	}
+}
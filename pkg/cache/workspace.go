@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+	"go.uber.org/zap"
+)
+
+// RefOccurrence is one taskRef/pipelineRef found while scanning a file on
+// disk, paired with the file it was found in.
+type RefOccurrence struct {
+	URI protocol.DocumentURI
+	Ref Ref
+}
+
+// fileEntry is everything the workspace index keeps per file.
+type fileEntry struct {
+	symbols []Symbol
+	refs    []Ref
+}
+
+// WorkspaceIndex is a disk-backed index of Tekton resource definitions and
+// references under one workspace folder. It is seeded by walking the folder
+// once and kept up to date by DidChangeWatchedFiles notifications, so that
+// taskRef/pipelineRef completion and navigation work against files the
+// client hasn't opened.
+type WorkspaceIndex struct {
+	logger *zap.Logger
+	root   string
+
+	mu      sync.RWMutex
+	entries map[protocol.DocumentURI]fileEntry
+}
+
+// NewWorkspaceIndex creates a WorkspaceIndex rooted at folder and performs
+// the initial scan. Scan errors are logged rather than returned: a folder
+// that can't be walked (e.g. not yet present on disk) simply starts with an
+// empty index, which later watched-file events can still add to.
+func NewWorkspaceIndex(logger *zap.Logger, folder protocol.DocumentURI) *WorkspaceIndex {
+	idx := &WorkspaceIndex{
+		logger:  logger,
+		root:    uri.URI(folder).Filename(),
+		entries: make(map[protocol.DocumentURI]fileEntry),
+	}
+	if idx.root != "" {
+		if err := idx.scan(); err != nil {
+			logger.Warn("failed to scan workspace folder", zap.String("root", idx.root), zap.Error(err))
+		}
+	}
+	return idx
+}
+
+func isYAMLPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// scan walks the whole workspace folder and rebuilds the index from
+// scratch.
+func (idx *WorkspaceIndex) scan() error {
+	entries := make(map[protocol.DocumentURI]fileEntry)
+
+	err := filepath.Walk(idx.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !isYAMLPath(path) {
+			return nil
+		}
+
+		docURI := protocol.DocumentURI(uri.File(path))
+		if entry, ok := scanFile(path); ok {
+			entries[docURI] = entry
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	idx.entries = entries
+	idx.mu.Unlock()
+	return nil
+}
+
+// Update re-parses the single file at uri and refreshes its entries in the
+// index, in response to a DidChangeWatchedFiles Created/Changed event.
+func (idx *WorkspaceIndex) Update(docURI protocol.DocumentURI) {
+	path := uri.URI(docURI).Filename()
+	entry, ok := scanFile(path)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !ok {
+		delete(idx.entries, docURI)
+		return
+	}
+	idx.entries[docURI] = entry
+}
+
+// Remove drops every symbol and ref that came from uri, in response to a
+// DidChangeWatchedFiles Deleted event.
+func (idx *WorkspaceIndex) Remove(docURI protocol.DocumentURI) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	delete(idx.entries, docURI)
+}
+
+// Lookup returns every known symbol of the given kind and name.
+func (idx *WorkspaceIndex) Lookup(kind ResourceKind, name string) []Symbol {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var found []Symbol
+	for _, entry := range idx.entries {
+		for _, s := range entry.symbols {
+			if s.Kind == kind && s.Name == name {
+				found = append(found, s)
+			}
+		}
+	}
+	return found
+}
+
+// All returns every symbol of the given kind known to the index.
+func (idx *WorkspaceIndex) All(kind ResourceKind) []Symbol {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var all []Symbol
+	for _, entry := range idx.entries {
+		for _, s := range entry.symbols {
+			if s.Kind == kind {
+				all = append(all, s)
+			}
+		}
+	}
+	return all
+}
+
+// RefsByName returns every taskRef/pipelineRef occurrence of the given kind
+// and name known to the index.
+func (idx *WorkspaceIndex) RefsByName(kind RefKind, name string) []RefOccurrence {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var found []RefOccurrence
+	for docURI, entry := range idx.entries {
+		for _, ref := range entry.refs {
+			if ref.Kind == kind && ref.Name == name {
+				found = append(found, RefOccurrence{URI: docURI, Ref: ref})
+			}
+		}
+	}
+	return found
+}
+
+// scanFile reads and parses a single file from disk, returning its
+// referenceable symbols and refs. Read or parse failures simply yield no
+// entry; they are surfaced to the user as diagnostics when the file is
+// open, not here.
+func scanFile(path string) (fileEntry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fileEntry{}, false
+	}
+
+	file, err := parseYAML(string(data))
+	if err != nil {
+		return fileEntry{}, false
+	}
+
+	docURI := protocol.DocumentURI(uri.File(path))
+	entry := fileEntry{
+		symbols: scanSymbols(docURI, file),
+		refs:    FindRefs(file),
+	}
+	if len(entry.symbols) == 0 && len(entry.refs) == 0 {
+		return fileEntry{}, false
+	}
+	return entry, true
+}
@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// NodeAt resolves a YAML path (e.g. "$.spec.params" or "$.spec.tasks") against
+// root and returns the node found there, whatever its kind. Callers type-
+// assert the result to whichever ast.Node they expect (commonly
+// *ast.SequenceNode or *ast.MappingNode).
+func NodeAt(root ast.Node, path string) (ast.Node, bool) {
+	p, err := yaml.PathString(path)
+	if err != nil {
+		return nil, false
+	}
+
+	node, err := p.FilterNode(root)
+	if err != nil || node == nil {
+		return nil, false
+	}
+	return node, true
+}
@@ -0,0 +1,82 @@
+// Package diagnostics turns a cache.Snapshot into the set of
+// protocol.Diagnostic values published back to the client. It is the single
+// place schema validation, cross-file resolution, and other semantic checks
+// hang off of, so that every text-sync event produces a consistent,
+// whole-snapshot diagnostics pass instead of ad hoc per-request checks.
+package diagnostics
+
+import (
+	"context"
+
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"github.com/tektoncd/tekton-lsp/pkg/handlers"
+	"go.lsp.dev/protocol"
+	"go.uber.org/zap"
+)
+
+// PublishFunc publishes diagnostics for a single document, matching
+// protocol.Client.PublishDiagnostics.
+type PublishFunc func(ctx context.Context, params *protocol.PublishDiagnosticsParams) error
+
+// Runner runs the diagnostics pass for every open file in a snapshot and
+// publishes the results, bailing out early if ctx is cancelled by a newer
+// snapshot superseding this one.
+type Runner struct {
+	logger   *zap.Logger
+	registry *handlers.Registry
+	publish  PublishFunc
+}
+
+// NewRunner creates a Runner that publishes diagnostics via publish, using
+// registry to look up each file's kind-specific checks.
+func NewRunner(logger *zap.Logger, registry *handlers.Registry, publish PublishFunc) *Runner {
+	return &Runner{logger: logger, registry: registry, publish: publish}
+}
+
+// Diagnose is a cache.DiagnoseFunc: it computes diagnostics for every file
+// in snap and publishes one PublishDiagnostics notification per file.
+func (r *Runner) Diagnose(ctx context.Context, snap *cache.Snapshot) {
+	for _, pf := range snap.Files() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		diags, err := r.diagnoseFile(ctx, snap.View(), pf)
+		if err != nil {
+			r.logger.Warn("diagnostics provider failed", zap.String("uri", string(pf.URI)), zap.Error(err))
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := r.publish(ctx, &protocol.PublishDiagnosticsParams{
+			URI:         pf.URI,
+			Version:     uint32(pf.Version),
+			Diagnostics: diags,
+		}); err != nil {
+			r.logger.Warn("failed to publish diagnostics", zap.String("uri", string(pf.URI)), zap.Error(err))
+		}
+	}
+}
+
+// diagnoseFile runs every check that applies to a single parsed file: a
+// universal YAML-syntax check, plus whatever the file kind's registered
+// Handler contributes.
+func (r *Runner) diagnoseFile(ctx context.Context, view *cache.View, pf *cache.ParsedFile) ([]protocol.Diagnostic, error) {
+	if pf.ParseErr != nil {
+		return []protocol.Diagnostic{
+			{
+				Range: protocol.Range{
+					Start: protocol.Position{Line: 0, Character: 0},
+					End:   protocol.Position{Line: 0, Character: 0},
+				},
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   "tekton-lsp",
+				Message:  pf.ParseErr.Error(),
+			},
+		}, nil
+	}
+
+	return r.registry.For(pf.Kind).Diagnostics(ctx, handlers.Context{View: view, File: pf})
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/tektoncd/tekton-lsp/pkg/rules"
+	"go.lsp.dev/protocol"
+)
+
+// Base implements Handler with no-op responses for every capability, except
+// Diagnostics and CodeActions, which every kind gets for free from pkg/rules:
+// Check and Fixes are keyed by the file's own kind, so a kind with no
+// registered rules (the Trigger kinds, currently) just gets nothing back.
+// Kind handlers embed Base and override only the methods they need
+// kind-specific behavior for, the same way lspHandler itself used to stub
+// out the whole protocol.Server interface before this registry existed.
+type Base struct{}
+
+func (Base) CodeActions(ctx context.Context, hctx Context, params *protocol.CodeActionParams) ([]protocol.CodeAction, error) {
+	t := rules.Target{View: hctx.View, File: hctx.File}
+	return rules.Fixes(t, params.Context.Diagnostics), nil
+}
+
+func (Base) Completions(ctx context.Context, hctx Context, params *protocol.CompletionParams) (*protocol.CompletionList, error) {
+	return nil, nil
+}
+
+func (Base) Hover(ctx context.Context, hctx Context, params *protocol.HoverParams) (*protocol.Hover, error) {
+	return nil, nil
+}
+
+func (Base) Diagnostics(ctx context.Context, hctx Context) ([]protocol.Diagnostic, error) {
+	return rules.Check(ctx, rules.Target{View: hctx.View, File: hctx.File}), nil
+}
+
+func (Base) DocumentSymbols(ctx context.Context, hctx Context) ([]interface{}, error) {
+	return nil, nil
+}
+
+func (Base) FoldingRange(ctx context.Context, hctx Context) ([]protocol.FoldingRange, error) {
+	return nil, nil
+}
+
+func (Base) SemanticTokens(ctx context.Context, hctx Context) (*protocol.SemanticTokens, error) {
+	return nil, nil
+}
@@ -0,0 +1,35 @@
+// Package handlers implements the per-file-kind capability providers the
+// server dispatches LSP requests to, keyed by the Tekton resource kind of
+// the document a request targets. This mirrors gopls' approach of keying
+// supported code actions (and other capabilities) by file kind, so adding
+// Tekton-flavor-specific behavior doesn't grow one giant switch in
+// pkg/server.
+package handlers
+
+import (
+	"context"
+
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"go.lsp.dev/protocol"
+)
+
+// Context carries the resolved view and file a capability call targets.
+type Context struct {
+	View *cache.View
+	File *cache.ParsedFile
+}
+
+// Handler is the set of capabilities a file kind can provide. Every method
+// except Diagnostics is dispatched to from the matching pkg/server/lspHandler
+// method after it has resolved which file (and therefore which Handler) a
+// request targets; Diagnostics is called by pkg/diagnostics' Runner for
+// every open file on each snapshot change.
+type Handler interface {
+	CodeActions(ctx context.Context, hctx Context, params *protocol.CodeActionParams) ([]protocol.CodeAction, error)
+	Completions(ctx context.Context, hctx Context, params *protocol.CompletionParams) (*protocol.CompletionList, error)
+	Hover(ctx context.Context, hctx Context, params *protocol.HoverParams) (*protocol.Hover, error)
+	Diagnostics(ctx context.Context, hctx Context) ([]protocol.Diagnostic, error)
+	DocumentSymbols(ctx context.Context, hctx Context) ([]interface{}, error)
+	FoldingRange(ctx context.Context, hctx Context) ([]protocol.FoldingRange, error)
+	SemanticTokens(ctx context.Context, hctx Context) (*protocol.SemanticTokens, error)
+}
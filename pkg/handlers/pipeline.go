@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"go.lsp.dev/protocol"
+)
+
+// PipelineHandler provides capabilities for Pipeline manifests: completing
+// taskRef/pipelineRef names against the workspace symbol index, plus the
+// pkg/rules-backed diagnostics/code actions Base already provides.
+type PipelineHandler struct {
+	Base
+}
+
+func (PipelineHandler) Completions(ctx context.Context, hctx Context, params *protocol.CompletionParams) (*protocol.CompletionList, error) {
+	return completeRef(hctx, params)
+}
+
+// completeRef implements taskRef/pipelineRef name completion: it offers
+// every known resource of the referenced kind, attaching an
+// AdditionalTextEdit to embed the manifest inline when it doesn't already
+// live in the file being edited.
+func completeRef(hctx Context, params *protocol.CompletionParams) (*protocol.CompletionList, error) {
+	if hctx.File == nil || hctx.File.AST == nil {
+		return nil, nil
+	}
+
+	ref, ok := cache.RefAt(hctx.File.AST, params.Position)
+	if !ok {
+		return nil, nil
+	}
+
+	var items []protocol.CompletionItem
+	for _, kind := range ref.Kind.ReferentKinds() {
+		for _, sym := range hctx.View.Symbols(kind) {
+			items = append(items, completionItemFor(hctx, sym))
+		}
+	}
+
+	return &protocol.CompletionList{IsIncomplete: false, Items: items}, nil
+}
+
+// completionItemFor builds the CompletionItem for a taskRef/pipelineRef
+// candidate. When sym is defined in a file other than the one being edited,
+// an AdditionalTextEdit embeds the missing manifest as a new YAML document
+// appended to the current file, the same way gopls' completion for an
+// unimported package inserts the missing import as a side edit.
+func completionItemFor(hctx Context, sym cache.Symbol) protocol.CompletionItem {
+	item := protocol.CompletionItem{
+		Label:  sym.Name,
+		Kind:   protocol.CompletionItemKindValue,
+		Detail: string(sym.Kind),
+	}
+
+	if sym.URI == hctx.File.URI || sym.Text == "" {
+		return item
+	}
+
+	end := endOfDocument(hctx.File.Text)
+	item.AdditionalTextEdits = []protocol.TextEdit{
+		{
+			Range:   protocol.Range{Start: end, End: end},
+			NewText: "\n---\n" + strings.TrimRight(sym.Text, "\n") + "\n",
+		},
+	}
+	return item
+}
+
+// endOfDocument returns the position just past the last character of text.
+func endOfDocument(text string) protocol.Position {
+	lines := strings.Split(text, "\n")
+	last := lines[len(lines)-1]
+	return protocol.Position{
+		Line:      uint32(len(lines) - 1),
+		Character: uint32(len([]rune(last))),
+	}
+}
@@ -0,0 +1,8 @@
+package handlers
+
+// PipelineRunHandler provides capabilities for PipelineRun manifests. It has
+// no overrides beyond the Base no-ops and the pkg/rules-backed
+// diagnostics/code actions Base already provides.
+type PipelineRunHandler struct {
+	Base
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+)
+
+// Registry maps a detected Tekton kind to the Handler that implements its
+// capabilities, falling back to a plain Base handler for plain YAML or any
+// kind we don't have a dedicated provider for yet.
+type Registry struct {
+	handlers map[cache.ResourceKind]Handler
+	fallback Handler
+}
+
+// NewRegistry builds the registry with the built-in handler for every
+// Tekton kind pkg/cache currently recognizes.
+func NewRegistry() *Registry {
+	r := &Registry{
+		handlers: make(map[cache.ResourceKind]Handler),
+		fallback: Base{},
+	}
+
+	task := &TaskHandler{}
+	r.Register(cache.KindTask, task)
+	r.Register(cache.KindClusterTask, task)
+
+	r.Register(cache.KindPipeline, &PipelineHandler{})
+	r.Register(cache.KindPipelineRun, &PipelineRunHandler{})
+	r.Register(cache.KindTaskRun, &TaskRunHandler{})
+	r.Register(cache.KindStepAction, &StepActionHandler{})
+
+	trigger := &TriggerHandler{}
+	r.Register(cache.KindTriggerTemplate, trigger)
+	r.Register(cache.KindTriggerBinding, trigger)
+	r.Register(cache.KindClusterTriggerBinding, trigger)
+	r.Register(cache.KindEventListener, trigger)
+
+	return r
+}
+
+// Register installs h as the handler for kind, overwriting any existing
+// registration.
+func (r *Registry) Register(kind cache.ResourceKind, h Handler) {
+	r.handlers[kind] = h
+}
+
+// For returns the handler registered for kind, or the fallback Base handler
+// if none was registered (plain YAML, or a kind pkg/cache doesn't
+// recognize).
+func (r *Registry) For(kind cache.ResourceKind) Handler {
+	if h, ok := r.handlers[kind]; ok {
+		return h
+	}
+	return r.fallback
+}
@@ -0,0 +1,8 @@
+package handlers
+
+// StepActionHandler provides capabilities for StepAction manifests. It has
+// no overrides beyond the Base no-ops and the pkg/rules-backed
+// diagnostics/code actions Base already provides.
+type StepActionHandler struct {
+	Base
+}
@@ -0,0 +1,8 @@
+package handlers
+
+// TaskHandler provides capabilities for Task and ClusterTask manifests. It
+// has no overrides beyond the Base no-ops and the pkg/rules-backed
+// diagnostics/code actions Base already provides.
+type TaskHandler struct {
+	Base
+}
@@ -0,0 +1,8 @@
+package handlers
+
+// TaskRunHandler provides capabilities for TaskRun manifests. It has no
+// overrides beyond the Base no-ops and the pkg/rules-backed
+// diagnostics/code actions Base already provides.
+type TaskRunHandler struct {
+	Base
+}
@@ -0,0 +1,8 @@
+package handlers
+
+// TriggerHandler provides capabilities shared by the Tekton Triggers kinds
+// (TriggerTemplate, TriggerBinding, ClusterTriggerBinding, EventListener).
+// It has no overrides yet beyond the Base no-ops.
+type TriggerHandler struct {
+	Base
+}
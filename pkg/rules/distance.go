@@ -0,0 +1,59 @@
+package rules
+
+// levenshtein returns the edit distance between a and b. It backs the
+// "rename to closest match" quick fixes: when a reference points at a name
+// that isn't declared, we offer to rename it to whichever declared name is
+// nearest, rather than guessing blindly.
+func levenshtein(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+// closestMatch returns whichever name in candidates is nearest to target by
+// edit distance, within a small threshold, or "" if nothing is close
+// enough to be a plausible typo fix.
+func closestMatch(target string, candidates []string) string {
+	const threshold = 2
+	best := ""
+	bestDist := threshold + 1
+	for _, cand := range candidates {
+		d := levenshtein(target, cand)
+		if d < bestDist {
+			bestDist = d
+			best = cand
+		}
+	}
+	return best
+}
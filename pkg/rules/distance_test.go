@@ -0,0 +1,42 @@
+package rules
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"foo", "foo", 0},
+		{"foo", "", 3},
+		{"kitten", "sitting", 3},
+		{"params", "param", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestClosestMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		candidates []string
+		want       string
+	}{
+		{"exact match", "foo", []string{"foo", "bar"}, "foo"},
+		{"close typo", "imag", []string{"image", "name"}, "image"},
+		{"nothing close enough", "xyz", []string{"image", "name"}, ""},
+		{"no candidates", "foo", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closestMatch(tt.target, tt.candidates); got != tt.want {
+				t.Errorf("closestMatch(%q, %v) = %q, want %q", tt.target, tt.candidates, got, tt.want)
+			}
+		})
+	}
+}
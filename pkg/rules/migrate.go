@@ -0,0 +1,86 @@
+package rules
+
+import (
+	"context"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"go.lsp.dev/protocol"
+)
+
+const sourceMigration Source = "migration"
+
+func init() {
+	Register(sourceMigration, fixMigration, checkMigration,
+		cache.KindTask, cache.KindClusterTask, cache.KindPipeline)
+}
+
+// checkMigration flags the removed PipelineResources-style spec.resources
+// field on a v1beta1 manifest, which the v1 API replaced with
+// spec.workspaces.
+func checkMigration(ctx context.Context, t Target) []protocol.Diagnostic {
+	doc := primaryDoc(t.File)
+	if doc == nil {
+		return nil
+	}
+
+	apiVersion, _, ok := cache.StringFieldAt(doc, "$.apiVersion")
+	if !ok || !strings.Contains(apiVersion, "v1beta1") {
+		return nil
+	}
+
+	key, ok := resourcesKey(doc)
+	if !ok {
+		return nil
+	}
+
+	return []protocol.Diagnostic{{
+		Range:    key,
+		Severity: protocol.DiagnosticSeverityWarning,
+		Source:   string(sourceMigration),
+		Message:  "spec.resources was removed in the v1 API; migrate to spec.workspaces",
+	}}
+}
+
+// resourcesKey returns the range of the spec.resources key itself, so the
+// quick fix can rename just the key text rather than the whole field.
+func resourcesKey(doc ast.Node) (protocol.Range, bool) {
+	spec, ok := cache.NodeAt(doc, "$.spec")
+	if !ok {
+		return protocol.Range{}, false
+	}
+	mapping, ok := spec.(*ast.MappingNode)
+	if !ok {
+		return protocol.Range{}, false
+	}
+
+	for _, mv := range mapping.Values {
+		if mv.Key.String() != "resources" {
+			continue
+		}
+		str, ok := mv.Key.(*ast.StringNode)
+		if !ok {
+			return protocol.Range{}, false
+		}
+		return cache.RangeOf(str, str.Value), true
+	}
+	return protocol.Range{}, false
+}
+
+// fixMigration renames the spec.resources key to spec.workspaces. It only
+// rewrites the key: an input/output PipelineResource doesn't map onto a
+// single workspace binding, so the body underneath still needs a human to
+// adjust.
+func fixMigration(t Target, diag protocol.Diagnostic) (protocol.CodeAction, bool) {
+	return protocol.CodeAction{
+		Title:       "Rename spec.resources to spec.workspaces",
+		Kind:        protocol.QuickFix,
+		Diagnostics: []protocol.Diagnostic{diag},
+		Edit: &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentURI][]protocol.TextEdit{
+				t.File.URI: {{Range: diag.Range, NewText: "workspaces"}},
+			},
+		},
+	}, true
+}
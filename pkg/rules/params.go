@@ -0,0 +1,184 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"go.lsp.dev/protocol"
+)
+
+const sourceParams Source = "params"
+
+func init() {
+	Register(sourceParams, fixParams, checkParams,
+		cache.KindTask, cache.KindClusterTask, cache.KindStepAction, cache.KindPipeline)
+}
+
+// paramRefPattern matches Tekton's variable substitution syntax for
+// parameters: $(params.name) and $(params["name"]).
+var paramRefPattern = regexp.MustCompile(`\$\(params(?:\.([A-Za-z0-9_-]+)|\[["']([A-Za-z0-9_-]+)["']\])\)`)
+
+// checkParams reports $(params.x) references to a parameter the document
+// never declares in spec.params, and declared parameters that nothing ever
+// references.
+func checkParams(ctx context.Context, t Target) []protocol.Diagnostic {
+	doc := primaryDoc(t.File)
+	if doc == nil {
+		return nil
+	}
+
+	declared := declaredParams(doc)
+	used := map[string]bool{}
+
+	var diags []protocol.Diagnostic
+	walkStrings(doc, func(node *ast.StringNode, value string) {
+		for _, idx := range paramRefPattern.FindAllStringSubmatchIndex(value, -1) {
+			match := value[idx[0]:idx[1]]
+			name := ""
+			nameStart, nameEnd := 0, 0
+			switch {
+			case idx[2] != -1:
+				name = value[idx[2]:idx[3]]
+				nameStart, nameEnd = idx[2]-idx[0], idx[3]-idx[0]
+			case idx[4] != -1:
+				name = value[idx[4]:idx[5]]
+				nameStart, nameEnd = idx[4]-idx[0], idx[5]-idx[0]
+			}
+			used[name] = true
+			if _, ok := declared[name]; ok {
+				continue
+			}
+
+			names := make([]string, 0, len(declared))
+			for n := range declared {
+				names = append(names, n)
+			}
+
+			diags = append(diags, protocol.Diagnostic{
+				Range:    cache.RangeOfIndex(node, value, idx[0], idx[1]-idx[0]),
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   string(sourceParams),
+				Message:  fmt.Sprintf("undeclared parameter reference $(params.%s)", name),
+				Data: map[string]interface{}{
+					"missing":   name,
+					"closest":   closestMatch(name, names),
+					"match":     match,
+					"nameStart": float64(nameStart),
+					"nameEnd":   float64(nameEnd),
+				},
+			})
+		}
+	})
+
+	for name, rng := range declared {
+		if used[name] {
+			continue
+		}
+		diags = append(diags, protocol.Diagnostic{
+			Range:    rng,
+			Severity: protocol.DiagnosticSeverityWarning,
+			Source:   string(sourceParams),
+			Message:  fmt.Sprintf("parameter %q is declared but never referenced", name),
+		})
+	}
+
+	return diags
+}
+
+// declaredParams returns every name declared in spec.params, with the
+// range of its own name field.
+func declaredParams(doc ast.Node) map[string]protocol.Range {
+	out := map[string]protocol.Range{}
+
+	node, ok := cache.NodeAt(doc, "$.spec.params")
+	if !ok {
+		return out
+	}
+	seq, ok := node.(*ast.SequenceNode)
+	if !ok {
+		return out
+	}
+
+	for _, item := range seq.Values {
+		name, rng, ok := cache.StringFieldAt(item, "$.name")
+		if !ok {
+			continue
+		}
+		out[name] = rng
+	}
+	return out
+}
+
+// fixParams resolves the "undeclared parameter reference" diagnostic:
+// rename the reference to the closest declared parameter if one is close
+// enough to plausibly be a typo, otherwise declare the missing parameter.
+func fixParams(t Target, diag protocol.Diagnostic) (protocol.CodeAction, bool) {
+	missing := stringField(diag.Data, "missing")
+	if missing == "" {
+		return protocol.CodeAction{}, false
+	}
+
+	if closest := stringField(diag.Data, "closest"); closest != "" {
+		return renameParamFix(t, diag, closest), true
+	}
+	return declareParamFix(t, diag, missing)
+}
+
+func renameParamFix(t Target, diag protocol.Diagnostic, closest string) protocol.CodeAction {
+	match := stringField(diag.Data, "match")
+	nameStart, nameEnd := intField(diag.Data, "nameStart"), intField(diag.Data, "nameEnd")
+	corrected := match[:nameStart] + closest + match[nameEnd:]
+
+	return protocol.CodeAction{
+		Title:       fmt.Sprintf("Rename to closest parameter %q", closest),
+		Kind:        protocol.QuickFix,
+		Diagnostics: []protocol.Diagnostic{diag},
+		Edit: &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentURI][]protocol.TextEdit{
+				t.File.URI: {{Range: diag.Range, NewText: corrected}},
+			},
+		},
+	}
+}
+
+// declareParamFix adds a new entry to spec.params for the missing
+// parameter, matching the indentation of the last existing entry. It only
+// fires when spec.params already has at least one entry to anchor the
+// insertion point on; a document with no params declared at all is left
+// for the author to fix by hand.
+func declareParamFix(t Target, diag protocol.Diagnostic, missing string) (protocol.CodeAction, bool) {
+	doc := primaryDoc(t.File)
+	if doc == nil {
+		return protocol.CodeAction{}, false
+	}
+
+	node, ok := cache.NodeAt(doc, "$.spec.params")
+	if !ok {
+		return protocol.CodeAction{}, false
+	}
+	seq, ok := node.(*ast.SequenceNode)
+	if !ok {
+		return protocol.CodeAction{}, false
+	}
+
+	edit, ok := insertAfterLastSequenceItem(t.File.Text, seq, func(indent string) string {
+		return fmt.Sprintf("%s- name: %s\n%s  type: string\n", indent, missing, indent)
+	})
+	if !ok {
+		return protocol.CodeAction{}, false
+	}
+
+	return protocol.CodeAction{
+		Title:       fmt.Sprintf("Declare missing parameter %q", missing),
+		Kind:        protocol.QuickFix,
+		Diagnostics: []protocol.Diagnostic{diag},
+		Edit: &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentURI][]protocol.TextEdit{
+				t.File.URI: {edit},
+			},
+		},
+	}, true
+}
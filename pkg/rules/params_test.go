@@ -0,0 +1,83 @@
+package rules
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.lsp.dev/protocol"
+)
+
+// offsetOf converts a 0-indexed line/character position into a byte offset
+// into text. It assumes ASCII content, which is all these tests use.
+func offsetOf(text string, pos protocol.Position) int {
+	lines := strings.SplitAfter(text, "\n")
+	offset := 0
+	for i := 0; i < int(pos.Line); i++ {
+		offset += len(lines[i])
+	}
+	return offset + int(pos.Character)
+}
+
+// applyEdit splices a single TextEdit into text the way an editor would.
+func applyEdit(text string, edit protocol.TextEdit) string {
+	start := offsetOf(text, edit.Range.Start)
+	end := offsetOf(text, edit.Range.End)
+	return text[:start] + edit.NewText + text[end:]
+}
+
+// TestFixParamsRenamePreservesMultilineValue is a regression test for a
+// quick fix that spliced the repaired match into the diagnostic's narrow
+// Range (just the $(params.x) text) while computing NewText against the
+// whole, multi-line scalar value -- corrupting any reference that wasn't
+// the entire node value, such as one inside a multi-line `script: |` block.
+func TestFixParamsRenamePreservesMultilineValue(t *testing.T) {
+	const taskYAML = `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+    - name: name
+      type: string
+  steps:
+    - name: build
+      image: alpine
+      script: |
+        echo start
+        echo $(params.nam)
+        echo end
+`
+
+	uri := protocol.DocumentURI("file:///task.yaml")
+	target := parseTarget(t, uri, taskYAML)
+	diags := checkParams(context.Background(), target)
+
+	var diag protocol.Diagnostic
+	found := false
+	for _, d := range diags {
+		if d.Source == string(sourceParams) {
+			diag = d
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("checkParams() found no undeclared-reference diagnostic in: %+v", diags)
+	}
+
+	action, ok := fixParams(target, diag)
+	if !ok {
+		t.Fatal("fixParams() = _, false, want true")
+	}
+	edits := action.Edit.Changes[uri]
+	if len(edits) != 1 {
+		t.Fatalf("fix produced %d edits, want 1: %+v", len(edits), edits)
+	}
+
+	got := applyEdit(taskYAML, edits[0])
+	want := strings.Replace(taskYAML, "$(params.nam)", "$(params.name)", 1)
+	if got != want {
+		t.Errorf("applying fix produced:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,101 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"go.lsp.dev/protocol"
+)
+
+const sourceRequiredFields Source = "required-fields"
+
+func init() {
+	Register(sourceRequiredFields, nil, checkRequiredFields,
+		cache.KindTask, cache.KindClusterTask, cache.KindPipeline,
+		cache.KindPipelineRun, cache.KindTaskRun, cache.KindStepAction)
+}
+
+// checkRequiredFields is NOT a schema validator: this tree doesn't vendor
+// the Tekton CRD OpenAPI schemas (v1beta1 and v1), so there's no $ref/type
+// checking here, only a hand-picked list of fields every one of these
+// kinds requires to be minimally well-formed. It's deliberately narrow in
+// scope; a real OpenAPI-based validator covering the full schema (types,
+// enums, additionalProperties) is a separate, much larger piece of work.
+func checkRequiredFields(ctx context.Context, t Target) []protocol.Diagnostic {
+	doc := primaryDoc(t.File)
+	if doc == nil {
+		return nil
+	}
+
+	var diags []protocol.Diagnostic
+	if _, _, ok := cache.StringFieldAt(doc, "$.metadata.name"); !ok {
+		diags = append(diags, requiredFieldDiag("metadata.name is required"))
+	}
+
+	spec, ok := cache.NodeAt(doc, "$.spec")
+	if !ok {
+		diags = append(diags, requiredFieldDiag("spec is required"))
+		return diags
+	}
+
+	switch t.File.Kind {
+	case cache.KindPipelineRun:
+		_, hasRef := cache.NodeAt(spec, "$.pipelineRef")
+		_, hasSpec := cache.NodeAt(spec, "$.pipelineSpec")
+		if !hasRef && !hasSpec {
+			diags = append(diags, requiredFieldDiag("spec.pipelineRef or spec.pipelineSpec is required"))
+		}
+	case cache.KindTaskRun:
+		_, hasRef := cache.NodeAt(spec, "$.taskRef")
+		_, hasSpec := cache.NodeAt(spec, "$.taskSpec")
+		if !hasRef && !hasSpec {
+			diags = append(diags, requiredFieldDiag("spec.taskRef or spec.taskSpec is required"))
+		}
+	case cache.KindTask, cache.KindClusterTask:
+		diags = append(diags, checkSteps(spec)...)
+	case cache.KindStepAction:
+		if _, ok := cache.NodeAt(spec, "$.image"); !ok {
+			diags = append(diags, requiredFieldDiag("spec.image is required"))
+		}
+	}
+
+	return diags
+}
+
+// checkSteps reports any spec.steps[*] entry missing the name or image
+// every step requires.
+func checkSteps(spec ast.Node) []protocol.Diagnostic {
+	node, ok := cache.NodeAt(spec, "$.steps")
+	if !ok {
+		return nil
+	}
+	seq, ok := node.(*ast.SequenceNode)
+	if !ok {
+		return nil
+	}
+
+	var diags []protocol.Diagnostic
+	for i, step := range seq.Values {
+		if _, _, ok := cache.StringFieldAt(step, "$.name"); !ok {
+			diags = append(diags, requiredFieldDiag(fmt.Sprintf("spec.steps[%d].name is required", i)))
+		}
+		if _, _, ok := cache.StringFieldAt(step, "$.image"); !ok {
+			diags = append(diags, requiredFieldDiag(fmt.Sprintf("spec.steps[%d].image is required", i)))
+		}
+	}
+	return diags
+}
+
+// requiredFieldDiag builds a required-fields diagnostic anchored at the top
+// of the document: missing-field errors have no sensible node to point at
+// since the field in question doesn't exist.
+func requiredFieldDiag(message string) protocol.Diagnostic {
+	return protocol.Diagnostic{
+		Range:    protocol.Range{Start: protocol.Position{Line: 0}, End: protocol.Position{Line: 0}},
+		Severity: protocol.DiagnosticSeverityError,
+		Source:   string(sourceRequiredFields),
+		Message:  message,
+	}
+}
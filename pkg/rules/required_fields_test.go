@@ -0,0 +1,111 @@
+package rules
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.lsp.dev/protocol"
+)
+
+func TestCheckRequiredFieldsPipelineRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantMsg string
+	}{
+		{
+			name: "missing pipelineRef and pipelineSpec",
+			yaml: "apiVersion: tekton.dev/v1\n" +
+				"kind: PipelineRun\n" +
+				"metadata:\n" +
+				"  name: run\n" +
+				"spec:\n" +
+				"  timeout: 1h\n",
+			wantMsg: "spec.pipelineRef or spec.pipelineSpec is required",
+		},
+		{
+			name: "has pipelineRef",
+			yaml: "apiVersion: tekton.dev/v1\n" +
+				"kind: PipelineRun\n" +
+				"metadata:\n" +
+				"  name: run\n" +
+				"spec:\n" +
+				"  pipelineRef:\n" +
+				"    name: my-pipeline\n",
+			wantMsg: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := parseTarget(t, "file:///run.yaml", tt.yaml)
+			diags := checkRequiredFields(context.Background(), target)
+			if got := hasMessage(diags, tt.wantMsg); tt.wantMsg != "" && !got {
+				t.Errorf("checkRequiredFields() = %+v, want a diagnostic containing %q", diags, tt.wantMsg)
+			}
+			if tt.wantMsg == "" && len(diags) != 0 {
+				t.Errorf("checkRequiredFields() = %+v, want none", diags)
+			}
+		})
+	}
+}
+
+func TestCheckRequiredFieldsTaskRun(t *testing.T) {
+	yaml := "apiVersion: tekton.dev/v1\n" +
+		"kind: TaskRun\n" +
+		"metadata:\n" +
+		"  name: run\n" +
+		"spec:\n" +
+		"  timeout: 1h\n"
+
+	target := parseTarget(t, "file:///run.yaml", yaml)
+	diags := checkRequiredFields(context.Background(), target)
+	if !hasMessage(diags, "spec.taskRef or spec.taskSpec is required") {
+		t.Errorf("checkRequiredFields() = %+v, want spec.taskRef/taskSpec diagnostic", diags)
+	}
+}
+
+func TestCheckRequiredFieldsSteps(t *testing.T) {
+	yaml := "apiVersion: tekton.dev/v1\n" +
+		"kind: Task\n" +
+		"metadata:\n" +
+		"  name: build\n" +
+		"spec:\n" +
+		"  steps:\n" +
+		"    - name: build\n" +
+		"    - image: alpine\n"
+
+	target := parseTarget(t, "file:///task.yaml", yaml)
+	diags := checkRequiredFields(context.Background(), target)
+
+	if !hasMessage(diags, "spec.steps[0].image is required") {
+		t.Errorf("checkRequiredFields() = %+v, want steps[0].image diagnostic", diags)
+	}
+	if !hasMessage(diags, "spec.steps[1].name is required") {
+		t.Errorf("checkRequiredFields() = %+v, want steps[1].name diagnostic", diags)
+	}
+}
+
+func TestCheckRequiredFieldsStepAction(t *testing.T) {
+	yaml := "apiVersion: tekton.dev/v1\n" +
+		"kind: StepAction\n" +
+		"metadata:\n" +
+		"  name: my-step\n" +
+		"spec:\n" +
+		"  command: [\"echo\"]\n"
+
+	target := parseTarget(t, "file:///step.yaml", yaml)
+	diags := checkRequiredFields(context.Background(), target)
+	if !hasMessage(diags, "spec.image is required") {
+		t.Errorf("checkRequiredFields() = %+v, want spec.image diagnostic", diags)
+	}
+}
+
+func hasMessage(diags []protocol.Diagnostic, substr string) bool {
+	for _, d := range diags {
+		if strings.Contains(d.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+// Package rules implements the semantic (schema-adjacent) checks run over
+// Tekton manifests and the quick-fix CodeActions attached to them. Every
+// rule lives in its own file and registers itself from an init() function,
+// the same way pkg/handlers' Registry maps a capability to a file kind —
+// here a check and its quick fix are additionally keyed by diagnostic
+// Source, mirroring gopls' code-action-by-source dispatch.
+package rules
+
+import (
+	"context"
+
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"go.lsp.dev/protocol"
+)
+
+// Target carries the file (and its owning view) a rule checks or a fix
+// edits. It mirrors pkg/handlers.Context field-for-field but is declared
+// separately here rather than imported, since pkg/handlers' kind handlers
+// call into this package and importing the other way would cycle.
+type Target struct {
+	View *cache.View
+	File *cache.ParsedFile
+}
+
+// Source identifies which rule produced a Diagnostic. It is both the
+// Diagnostic.Source clients display and the key a quick fix registers
+// itself under, so CodeAction can route a diagnostic back to the fix that
+// knows how to resolve it.
+type Source string
+
+// CheckFunc runs one rule against a single parsed file and returns the
+// diagnostics it found.
+type CheckFunc func(ctx context.Context, t Target) []protocol.Diagnostic
+
+// FixFunc synthesizes the quick-fix CodeAction for a single diagnostic
+// previously produced by the rule registered under the same Source. It
+// returns false if this particular diagnostic can't be fixed (for example,
+// because the Data payload it needs is missing).
+type FixFunc func(t Target, diag protocol.Diagnostic) (protocol.CodeAction, bool)
+
+type key struct {
+	kind   cache.ResourceKind
+	source Source
+}
+
+var (
+	checks = map[cache.ResourceKind][]CheckFunc{}
+	fixes  = map[key]FixFunc{}
+)
+
+// Register adds check to the rules run for every document of the given
+// kinds, and installs fix (if non-nil) as the quick-fix provider for
+// diagnostics it reports with the given source. It is meant to be called
+// from each rule file's init() function.
+func Register(source Source, fix FixFunc, check CheckFunc, kinds ...cache.ResourceKind) {
+	for _, kind := range kinds {
+		checks[kind] = append(checks[kind], check)
+		if fix != nil {
+			fixes[key{kind: kind, source: source}] = fix
+		}
+	}
+}
+
+// Check runs every rule registered for t.File.Kind and returns their
+// combined diagnostics.
+func Check(ctx context.Context, t Target) []protocol.Diagnostic {
+	var diags []protocol.Diagnostic
+	for _, check := range checks[t.File.Kind] {
+		diags = append(diags, check(ctx, t)...)
+	}
+	return diags
+}
+
+// Fix returns the quick-fix CodeAction for diag, if the rule that produced
+// it (identified by t.File.Kind and diag.Source) registered one.
+func Fix(t Target, diag protocol.Diagnostic) (protocol.CodeAction, bool) {
+	fix, ok := fixes[key{kind: t.File.Kind, source: Source(diag.Source)}]
+	if !ok {
+		return protocol.CodeAction{}, false
+	}
+	return fix(t, diag)
+}
+
+// Fixes returns every quick-fix CodeAction available for diags, the set a
+// CodeAction request's params.Context.Diagnostics resolves to.
+func Fixes(t Target, diags []protocol.Diagnostic) []protocol.CodeAction {
+	var actions []protocol.CodeAction
+	for _, diag := range diags {
+		if action, ok := Fix(t, diag); ok {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
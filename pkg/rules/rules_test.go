@@ -0,0 +1,29 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"go.lsp.dev/protocol"
+	"go.uber.org/zap"
+)
+
+// parseTarget opens text as a document and returns the Target the
+// CheckFunc/FixFunc pairs in this package operate on, the same way a
+// handler builds one from an hctx.
+func parseTarget(t *testing.T, uri protocol.DocumentURI, text string) Target {
+	t.Helper()
+
+	v := cache.NewView(zap.NewNop(), "file:///", func(context.Context, *cache.Snapshot) {})
+	v.DidOpen(uri, "yaml", 1, text)
+
+	pf, ok := v.Snapshot().File(uri)
+	if !ok {
+		t.Fatalf("Snapshot().File(%q) not found after DidOpen", uri)
+	}
+	if pf.ParseErr != nil {
+		t.Fatalf("parse error: %v", pf.ParseErr)
+	}
+	return Target{View: v, File: pf}
+}
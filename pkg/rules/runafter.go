@@ -0,0 +1,223 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"go.lsp.dev/protocol"
+)
+
+const sourceRunAfter Source = "runafter"
+
+func init() {
+	Register(sourceRunAfter, fixRunAfter, checkRunAfter, cache.KindPipeline)
+}
+
+// taskResultPattern matches a task consuming another task's results via
+// $(tasks.name.results.key).
+var taskResultPattern = regexp.MustCompile(`\$\(tasks\.([A-Za-z0-9_-]+)\.results\.`)
+
+// pipelineTask is one spec.tasks[*] entry, indexed by name for the
+// runAfter cycle/dependency checks below.
+type pipelineTask struct {
+	name     string
+	nameRng  protocol.Range
+	runAfter map[string]bool
+	node     ast.Node
+}
+
+// checkRunAfter reports two problems with spec.tasks ordering: explicit
+// runAfter dependency cycles, and a task that references another task's
+// result without a runAfter entry establishing that it actually runs
+// after it.
+func checkRunAfter(ctx context.Context, t Target) []protocol.Diagnostic {
+	doc := primaryDoc(t.File)
+	if doc == nil {
+		return nil
+	}
+
+	tasks := pipelineTasks(doc)
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	var diags []protocol.Diagnostic
+	diags = append(diags, runAfterCycles(tasks)...)
+	diags = append(diags, missingRunAfter(tasks)...)
+	return diags
+}
+
+func pipelineTasks(doc ast.Node) map[string]*pipelineTask {
+	node, ok := cache.NodeAt(doc, "$.spec.tasks")
+	if !ok {
+		return nil
+	}
+	seq, ok := node.(*ast.SequenceNode)
+	if !ok {
+		return nil
+	}
+
+	tasks := map[string]*pipelineTask{}
+	for _, item := range seq.Values {
+		name, rng, ok := cache.StringFieldAt(item, "$.name")
+		if !ok {
+			continue
+		}
+
+		t := &pipelineTask{name: name, nameRng: rng, runAfter: map[string]bool{}, node: item}
+		if ra, ok := cache.NodeAt(item, "$.runAfter"); ok {
+			if raSeq, ok := ra.(*ast.SequenceNode); ok {
+				for _, dep := range raSeq.Values {
+					if str, ok := dep.(*ast.StringNode); ok {
+						t.runAfter[str.Value] = true
+					}
+				}
+			}
+		}
+		tasks[name] = t
+	}
+	return tasks
+}
+
+// runAfterCycles detects a cycle in the runAfter dependency graph via DFS
+// coloring, reporting once at the first task found to be part of one.
+func runAfterCycles(tasks map[string]*pipelineTask) []protocol.Diagnostic {
+	const (
+		white = 0
+		gray  = 1
+		black = 2
+	)
+	color := map[string]int{}
+	inCycle := ""
+
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		t, ok := tasks[name]
+		if !ok {
+			return false
+		}
+		color[name] = gray
+		for dep := range t.runAfter {
+			if color[dep] == gray {
+				inCycle = dep
+				return true
+			}
+			if color[dep] == white && visit(dep) {
+				return true
+			}
+		}
+		color[name] = black
+		return false
+	}
+
+	for name := range tasks {
+		if color[name] == white && visit(name) {
+			return []protocol.Diagnostic{{
+				Range:    tasks[inCycle].nameRng,
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   string(sourceRunAfter),
+				Message:  fmt.Sprintf("task %q is part of a runAfter dependency cycle", inCycle),
+			}}
+		}
+	}
+	return nil
+}
+
+// missingRunAfter reports a task that consumes another task's results
+// without declaring runAfter on it, so the two tasks could run in the
+// wrong order (or in parallel) even though the underlying data has a real
+// dependency.
+func missingRunAfter(tasks map[string]*pipelineTask) []protocol.Diagnostic {
+	var diags []protocol.Diagnostic
+	for name, t := range tasks {
+		walkStrings(t.node, func(node *ast.StringNode, value string) {
+			for _, m := range taskResultPattern.FindAllStringSubmatch(value, -1) {
+				dep := m[1]
+				if dep == name {
+					continue
+				}
+				if _, ok := tasks[dep]; !ok || t.runAfter[dep] {
+					continue
+				}
+
+				diags = append(diags, protocol.Diagnostic{
+					Range:    cache.RangeOf(node, node.Value),
+					Severity: protocol.DiagnosticSeverityWarning,
+					Source:   string(sourceRunAfter),
+					Message:  fmt.Sprintf("task %q uses results from %q but doesn't runAfter it", name, dep),
+					Data: map[string]interface{}{
+						"task": name,
+						"dep":  dep,
+					},
+				})
+			}
+		})
+	}
+	return diags
+}
+
+// fixRunAfter adds the missing dependency to the task's runAfter list,
+// creating the list if the task doesn't have one yet.
+func fixRunAfter(t Target, diag protocol.Diagnostic) (protocol.CodeAction, bool) {
+	taskName := stringField(diag.Data, "task")
+	dep := stringField(diag.Data, "dep")
+	if taskName == "" || dep == "" {
+		return protocol.CodeAction{}, false
+	}
+
+	doc := primaryDoc(t.File)
+	if doc == nil {
+		return protocol.CodeAction{}, false
+	}
+	pt, ok := pipelineTasks(doc)[taskName]
+	if !ok {
+		return protocol.CodeAction{}, false
+	}
+
+	title := fmt.Sprintf("Add runAfter dependency on %q", dep)
+
+	var edit protocol.TextEdit
+	if ra, ok := cache.NodeAt(pt.node, "$.runAfter"); ok {
+		seq, ok := ra.(*ast.SequenceNode)
+		if !ok {
+			return protocol.CodeAction{}, false
+		}
+		e, ok := insertAfterLastSequenceItem(t.File.Text, seq, func(indent string) string {
+			return fmt.Sprintf("%s- %s\n", indent, dep)
+		})
+		if !ok {
+			return protocol.CodeAction{}, false
+		}
+		edit = e
+	} else {
+		tok := pt.node.GetToken()
+		if tok == nil || tok.Position.Line <= 0 {
+			return protocol.CodeAction{}, false
+		}
+		lines := strings.Split(t.File.Text, "\n")
+		line := tok.Position.Line - 1
+		if line < 0 || line >= len(lines) {
+			return protocol.CodeAction{}, false
+		}
+		indent := leadingWhitespace(lines[line])
+
+		pos := protocol.Position{Line: uint32(line + 1), Character: 0}
+		edit = protocol.TextEdit{
+			Range:   protocol.Range{Start: pos, End: pos},
+			NewText: fmt.Sprintf("%s  runAfter:\n%s    - %s\n", indent, indent, dep),
+		}
+	}
+
+	return protocol.CodeAction{
+		Title:       title,
+		Kind:        protocol.QuickFix,
+		Diagnostics: []protocol.Diagnostic{diag},
+		Edit: &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentURI][]protocol.TextEdit{t.File.URI: {edit}},
+		},
+	}, true
+}
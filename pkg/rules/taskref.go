@@ -0,0 +1,78 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"go.lsp.dev/protocol"
+)
+
+const sourceRef Source = "ref"
+
+func init() {
+	Register(sourceRef, fixRef, checkRefs, cache.KindPipeline, cache.KindTaskRun, cache.KindPipelineRun)
+}
+
+// checkRefs reports taskRef/pipelineRef names that don't resolve to any
+// known resource they can point at in the workspace: a Task or ClusterTask
+// for taskRef, a Pipeline for pipelineRef.
+func checkRefs(ctx context.Context, t Target) []protocol.Diagnostic {
+	if t.File == nil || t.File.AST == nil {
+		return nil
+	}
+
+	var diags []protocol.Diagnostic
+	for _, ref := range cache.FindRefs(t.File.AST) {
+		if refResolves(t, ref) {
+			continue
+		}
+
+		var names []string
+		for _, kind := range ref.Kind.ReferentKinds() {
+			for _, sym := range t.View.Symbols(kind) {
+				names = append(names, sym.Name)
+			}
+		}
+
+		diags = append(diags, protocol.Diagnostic{
+			Range:    ref.NameRange,
+			Severity: protocol.DiagnosticSeverityError,
+			Source:   string(sourceRef),
+			Message:  fmt.Sprintf("%s %q does not resolve to any known resource", ref.Kind, ref.Name),
+			Data: map[string]interface{}{
+				"closest": closestMatch(ref.Name, names),
+			},
+		})
+	}
+	return diags
+}
+
+func refResolves(t Target, ref cache.Ref) bool {
+	for _, kind := range ref.Kind.ReferentKinds() {
+		if len(t.View.Lookup(kind, ref.Name)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// fixRef renames an unresolved taskRef/pipelineRef to the closest known
+// name, when one is close enough to plausibly be a typo.
+func fixRef(t Target, diag protocol.Diagnostic) (protocol.CodeAction, bool) {
+	closest := stringField(diag.Data, "closest")
+	if closest == "" {
+		return protocol.CodeAction{}, false
+	}
+
+	return protocol.CodeAction{
+		Title:       fmt.Sprintf("Rename to closest match %q", closest),
+		Kind:        protocol.QuickFix,
+		Diagnostics: []protocol.Diagnostic{diag},
+		Edit: &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentURI][]protocol.TextEdit{
+				t.File.URI: {{Range: diag.Range, NewText: closest}},
+			},
+		},
+	}, true
+}
@@ -0,0 +1,145 @@
+package rules
+
+import (
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"go.lsp.dev/protocol"
+)
+
+// primaryDoc returns the first YAML document body in pf. Tekton manifests
+// are conventionally one resource per file, and every rule and quick fix in
+// this package that goes through primaryDoc only checks/edits that first
+// document; the taskRef/pipelineRef check (checkRefs) instead scans every
+// document in the file.
+func primaryDoc(pf *cache.ParsedFile) ast.Node {
+	if pf == nil || pf.AST == nil || len(pf.AST.Docs) == 0 {
+		return nil
+	}
+	return pf.AST.Docs[0].Body
+}
+
+// walkStrings calls fn for every *ast.StringNode scalar value found
+// anywhere under node (mapping values and sequence items, including block
+// scalars like `script: |`, whose value goccy/go-yaml parses as an
+// *ast.LiteralNode wrapping the *ast.StringNode; mapping keys are skipped
+// since none of this package's checks look for matches there).
+func walkStrings(node ast.Node, fn func(n *ast.StringNode, value string)) {
+	switch n := node.(type) {
+	case *ast.StringNode:
+		fn(n, n.Value)
+	case *ast.MappingNode:
+		for _, mv := range n.Values {
+			walkStrings(mv, fn)
+		}
+	case *ast.MappingValueNode:
+		walkStrings(n.Value, fn)
+	case *ast.SequenceNode:
+		for _, item := range n.Values {
+			walkStrings(item, fn)
+		}
+	case *ast.LiteralNode:
+		walkStrings(literalValue(n), fn)
+	}
+}
+
+// literalValue returns n's inner StringNode with its token position
+// corrected to the start of the block scalar's content. goccy/go-yaml
+// leaves that StringNode's own token pointing at the end of the scanned
+// block rather than its start, which would otherwise throw off every
+// offset RangeOfIndex computes for a match found inside a `script: |`
+// block; n.Start (the `|`/`>` indicator token) plus the content token's
+// own IndentNum give us the real start line and column.
+func literalValue(n *ast.LiteralNode) *ast.StringNode {
+	value := n.Value
+	if value == nil || value.Token == nil || n.Start == nil {
+		return value
+	}
+
+	fixed := *value.Token
+	pos := *fixed.Position
+	pos.Line = n.Start.Position.Line + 1
+	pos.Column = pos.IndentNum + 1
+	fixed.Position = &pos
+
+	corrected := *value
+	corrected.Token = &fixed
+	return &corrected
+}
+
+// leadingWhitespace returns the run of spaces/tabs at the start of line,
+// used to match a new line's indentation to its siblings when synthesizing
+// an insertion quick fix.
+func leadingWhitespace(line string) string {
+	i := 0
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	return line[:i]
+}
+
+// stringField extracts a string value from a Diagnostic.Data payload. Data
+// arrives as a map[string]interface{} once it has round-tripped through
+// the client as JSON (the normal path: params.Context.Diagnostics in a
+// CodeAction request is whatever the client echoes back), so every rule's
+// fix reads its payload this way rather than type-asserting a concrete Go
+// struct.
+func stringField(data interface{}, key string) string {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	s, _ := m[key].(string)
+	return s
+}
+
+// intField extracts an int value from a Diagnostic.Data payload the same
+// way stringField does; JSON numbers (including ones that never left this
+// process) decode into float64, so this reads through that regardless of
+// whether Data actually round-tripped through a client.
+func intField(data interface{}, key string) int {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	f, _ := m[key].(float64)
+	return int(f)
+}
+
+// insertAfterLastSequenceItem returns the TextEdit that inserts the text
+// itemText builds (given the matching indentation) right after the last
+// item in seq, or false if seq is empty or its source position can't be
+// resolved. Every "declare the missing X" quick fix in this package is a
+// sequence insertion of this shape (spec.params, spec.workspaces, a task's
+// runAfter list), so they all go through this one helper.
+func insertAfterLastSequenceItem(text string, seq *ast.SequenceNode, itemText func(indent string) string) (protocol.TextEdit, bool) {
+	if len(seq.Values) == 0 {
+		return protocol.TextEdit{}, false
+	}
+
+	last := seq.Values[len(seq.Values)-1]
+	tok := last.GetToken()
+	if tok == nil || tok.Position.Line <= 0 {
+		return protocol.TextEdit{}, false
+	}
+
+	lines := strings.Split(text, "\n")
+	lastLine := tok.Position.Line - 1
+	if lastLine < 0 || lastLine >= len(lines) {
+		return protocol.TextEdit{}, false
+	}
+	indent := leadingWhitespace(lines[lastLine])
+
+	insertLine := lastLine
+	for insertLine+1 < len(lines) {
+		next := lines[insertLine+1]
+		if strings.TrimSpace(next) == "" || len(leadingWhitespace(next)) <= len(indent) {
+			break
+		}
+		insertLine++
+	}
+
+	pos := protocol.Position{Line: uint32(insertLine + 1), Character: 0}
+	return protocol.TextEdit{Range: protocol.Range{Start: pos, End: pos}, NewText: itemText(indent)}, true
+}
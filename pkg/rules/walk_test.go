@@ -0,0 +1,109 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+)
+
+// paramsSeq parses text and returns the spec.params sequence node, failing
+// the test if either step comes up empty.
+func paramsSeq(t *testing.T, text string) *ast.SequenceNode {
+	t.Helper()
+
+	file, err := parser.ParseBytes([]byte(text), parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parser.ParseBytes() error = %v", err)
+	}
+
+	node, ok := cache.NodeAt(file.Docs[0].Body, "$.spec.params")
+	if !ok {
+		t.Fatalf("no spec.params found in:\n%s", text)
+	}
+	seq, ok := node.(*ast.SequenceNode)
+	if !ok {
+		t.Fatalf("spec.params is not a sequence: %T", node)
+	}
+	return seq
+}
+
+func TestInsertAfterLastSequenceItem(t *testing.T) {
+	text := "spec:\n" +
+		"  params:\n" +
+		"    - name: foo\n" +
+		"      type: string\n" +
+		"  steps:\n" +
+		"    - name: step1\n" +
+		"      image: alpine\n"
+
+	seq := paramsSeq(t, text)
+	edit, ok := insertAfterLastSequenceItem(text, seq, func(indent string) string {
+		return indent + "- name: bar\n" + indent + "  type: string\n"
+	})
+	if !ok {
+		t.Fatal("insertAfterLastSequenceItem() = false, want true")
+	}
+
+	// The insertion point must land right after params' own last item
+	// (and its continuation line), not after unrelated sibling sections
+	// like steps that happen to share the same indentation.
+	lines := strings.Split(text, "\n")
+	wantLine := uint32(4) // the start of "  steps:", right after "      type: string"
+	if edit.Range.Start.Line != wantLine {
+		t.Errorf("insertion line = %d, want %d (line %q)", edit.Range.Start.Line, wantLine, lines[edit.Range.Start.Line])
+	}
+	if strings.Contains(edit.NewText, "steps") {
+		t.Errorf("insertion text unexpectedly mentions steps: %q", edit.NewText)
+	}
+}
+
+func TestInsertAfterLastSequenceItemEmptySeq(t *testing.T) {
+	if _, ok := insertAfterLastSequenceItem("spec:\n  params:\n", &ast.SequenceNode{}, func(string) string { return "" }); ok {
+		t.Error("insertAfterLastSequenceItem() on an empty sequence = true, want false")
+	}
+}
+
+func TestLeadingWhitespace(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"    - name: foo", "    "},
+		{"no indent", ""},
+		{"\t\tindented", "\t\t"},
+	}
+	for _, tt := range tests {
+		if got := leadingWhitespace(tt.line); got != tt.want {
+			t.Errorf("leadingWhitespace(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestStringField(t *testing.T) {
+	data := map[string]interface{}{"missing": "foo", "count": 3}
+	if got := stringField(data, "missing"); got != "foo" {
+		t.Errorf("stringField(missing) = %q, want %q", got, "foo")
+	}
+	if got := stringField(data, "count"); got != "" {
+		t.Errorf("stringField(count) = %q, want empty (wrong type)", got)
+	}
+	if got := stringField(data, "absent"); got != "" {
+		t.Errorf("stringField(absent) = %q, want empty", got)
+	}
+	if got := stringField("not a map", "missing"); got != "" {
+		t.Errorf("stringField(non-map data) = %q, want empty", got)
+	}
+}
+
+func TestIntField(t *testing.T) {
+	data := map[string]interface{}{"nameStart": float64(3), "name": "foo"}
+	if got := intField(data, "nameStart"); got != 3 {
+		t.Errorf("intField(nameStart) = %d, want 3", got)
+	}
+	if got := intField(data, "name"); got != 0 {
+		t.Errorf("intField(name) = %d, want 0 (wrong type)", got)
+	}
+}
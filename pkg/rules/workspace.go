@@ -0,0 +1,129 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"go.lsp.dev/protocol"
+)
+
+const sourceWorkspace Source = "workspace"
+
+func init() {
+	Register(sourceWorkspace, fixWorkspace, checkWorkspaces, cache.KindPipeline)
+}
+
+// checkWorkspaces reports a task-level workspace binding
+// (spec.tasks[*].workspaces[*].workspace) that doesn't name any workspace
+// declared in spec.workspaces.
+func checkWorkspaces(ctx context.Context, t Target) []protocol.Diagnostic {
+	doc := primaryDoc(t.File)
+	if doc == nil {
+		return nil
+	}
+
+	declared := declaredWorkspaces(doc)
+
+	node, ok := cache.NodeAt(doc, "$.spec.tasks")
+	if !ok {
+		return nil
+	}
+	seq, ok := node.(*ast.SequenceNode)
+	if !ok {
+		return nil
+	}
+
+	var diags []protocol.Diagnostic
+	for _, task := range seq.Values {
+		bindings, ok := cache.NodeAt(task, "$.workspaces")
+		if !ok {
+			continue
+		}
+		bseq, ok := bindings.(*ast.SequenceNode)
+		if !ok {
+			continue
+		}
+
+		for _, binding := range bseq.Values {
+			name, rng, ok := cache.StringFieldAt(binding, "$.workspace")
+			if !ok || declared[name] {
+				continue
+			}
+			diags = append(diags, protocol.Diagnostic{
+				Range:    rng,
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   string(sourceWorkspace),
+				Message:  fmt.Sprintf("workspace %q is not declared in spec.workspaces", name),
+				Data: map[string]interface{}{
+					"missing": name,
+				},
+			})
+		}
+	}
+	return diags
+}
+
+func declaredWorkspaces(doc ast.Node) map[string]bool {
+	out := map[string]bool{}
+
+	node, ok := cache.NodeAt(doc, "$.spec.workspaces")
+	if !ok {
+		return out
+	}
+	seq, ok := node.(*ast.SequenceNode)
+	if !ok {
+		return out
+	}
+
+	for _, item := range seq.Values {
+		if name, _, ok := cache.StringFieldAt(item, "$.name"); ok {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+// fixWorkspace declares the missing workspace at the pipeline level,
+// matching the indentation of the last existing spec.workspaces entry. A
+// pipeline with no workspaces declared at all is left for the author to
+// fix by hand, same as the equivalent spec.params case.
+func fixWorkspace(t Target, diag protocol.Diagnostic) (protocol.CodeAction, bool) {
+	missing := stringField(diag.Data, "missing")
+	if missing == "" {
+		return protocol.CodeAction{}, false
+	}
+
+	doc := primaryDoc(t.File)
+	if doc == nil {
+		return protocol.CodeAction{}, false
+	}
+
+	node, ok := cache.NodeAt(doc, "$.spec.workspaces")
+	if !ok {
+		return protocol.CodeAction{}, false
+	}
+	seq, ok := node.(*ast.SequenceNode)
+	if !ok {
+		return protocol.CodeAction{}, false
+	}
+
+	edit, ok := insertAfterLastSequenceItem(t.File.Text, seq, func(indent string) string {
+		return fmt.Sprintf("%s- name: %s\n", indent, missing)
+	})
+	if !ok {
+		return protocol.CodeAction{}, false
+	}
+
+	return protocol.CodeAction{
+		Title:       fmt.Sprintf("Declare missing workspace %q", missing),
+		Kind:        protocol.QuickFix,
+		Diagnostics: []protocol.Diagnostic{diag},
+		Edit: &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentURI][]protocol.TextEdit{
+				t.File.URI: {edit},
+			},
+		},
+	}, true
+}
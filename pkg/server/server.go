@@ -3,35 +3,59 @@ package server
 import (
 	"context"
 	"fmt"
+	"io"
+	"net"
 
+	"github.com/tektoncd/tekton-lsp/pkg/cache"
+	"github.com/tektoncd/tekton-lsp/pkg/diagnostics"
+	"github.com/tektoncd/tekton-lsp/pkg/handlers"
+	"github.com/tektoncd/tekton-lsp/pkg/trace"
 	"go.lsp.dev/jsonrpc2"
 	"go.lsp.dev/protocol"
 	"go.uber.org/zap"
 )
 
-// Server implements the LSP server
+// Server holds the configuration shared by every LSP session it serves:
+// nothing here is mutated once NewServer returns, so it's safe for
+// concurrent connections to share a *Server. Per-connection state (the
+// document session, the client, the trace tracker, initialize/shutdown
+// flags) lives on lspHandler instead, one instance per connection.
 type Server struct {
-	logger *zap.Logger
-	client protocol.Client
-
-	// Server state
-	initialized  bool
-	shuttingDown bool
+	logger     *zap.Logger
+	registry   *handlers.Registry
+	traceLevel trace.Level
 }
 
-// NewServer creates a new LSP server instance
-func NewServer(logger *zap.Logger) *Server {
+// NewServer creates a new LSP server instance. traceLevel sets the initial
+// JSON-RPC wire tracing verbosity each connection's tracker starts at; it
+// can be changed at runtime by that connection's client via $/setTrace.
+func NewServer(logger *zap.Logger, traceLevel trace.Level) *Server {
 	return &Server{
-		logger: logger,
+		logger:     logger,
+		registry:   handlers.NewRegistry(),
+		traceLevel: traceLevel,
 	}
 }
 
-// Serve starts serving LSP requests
-func (s *Server) Serve(ctx context.Context, stream jsonrpc2.Stream) error {
+// TraceStream wraps rwc so that every byte read from or written to it is
+// recorded by a fresh trace.Tracker, before it is handed to
+// jsonrpc2.NewStream. Tracing is a no-op at trace.Off, which is the default.
+// The returned Tracker must be passed to the matching Serve call so that
+// $/setTrace and trace-to-client forwarding reach the same connection.
+func (s *Server) TraceStream(rwc io.ReadWriteCloser) (io.ReadWriteCloser, *trace.Tracker) {
+	tracer := trace.NewTracker(s.logger, s.traceLevel)
+	return tracer.Wrap(rwc, rwc, rwc), tracer
+}
+
+// Serve starts serving a single LSP session over stream, blocking until the
+// client disconnects or ctx is cancelled. tracer is the one returned by the
+// TraceStream call that wrapped stream's underlying connection.
+func (s *Server) Serve(ctx context.Context, stream jsonrpc2.Stream, tracer *trace.Tracker) error {
 	s.logger.Info("LSP server ready to accept connections")
 
 	// Create handler
-	handler := protocol.ServerHandler(&lspHandler{server: s}, nil)
+	h := &lspHandler{server: s, tracer: tracer}
+	handler := protocol.ServerHandler(h, nil)
 
 	// Create connection with handler
 	conn := jsonrpc2.NewConn(stream)
@@ -39,15 +63,100 @@ func (s *Server) Serve(ctx context.Context, stream jsonrpc2.Stream) error {
 
 	// Create client from connection
 	client := protocol.ClientDispatcher(conn, s.logger)
-	s.client = client
+	h.client = client
+
+	runner := diagnostics.NewRunner(s.logger, s.registry, client.PublishDiagnostics)
+	h.session = cache.NewSession(s.logger, runner.Diagnose)
+
+	tracer.SetNotifier(func(message string) {
+		if err := client.LogMessage(ctx, &protocol.LogMessageParams{Type: protocol.MessageTypeLog, Message: message}); err != nil {
+			s.logger.Warn("failed to forward trace to client", zap.Error(err))
+		}
+	})
 
 	<-conn.Done()
 	return conn.Err()
 }
 
-// lspHandler implements protocol.Server interface
+// ListenAndServe listens on the given network/address (e.g. network="tcp",
+// addr=":4389" or network="unix", addr="/tmp/tekton-lsp.sock") and serves an
+// LSP session over each accepted connection concurrently. Each connection
+// gets its own lspHandler with an isolated document session, client, and
+// trace tracker, so unrelated clients can't see each other's diagnostics or
+// initialize/shutdown state; ctx cancellation stops the listener and causes
+// ListenAndServe to return.
+//
+// This mirrors gopls' RunServerOnAddress/RunServerOnPort helpers, which exist
+// so editors, remote dev containers, and debuggers can attach to a long-lived
+// server instead of spawning one over stdio per session.
+func (s *Server) ListenAndServe(ctx context.Context, network, addr string) error {
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s %s: %w", network, addr, err)
+	}
+	defer ln.Close()
+
+	s.logger.Info("LSP server listening", zap.String("network", network), zap.String("address", ln.Addr().String()))
+
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		go s.serveConn(ctx, conn)
+	}
+}
+
+// serveConn wraps a single accepted connection in a jsonrpc2.Stream and runs
+// an LSP session over it until the client disconnects.
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	remote := conn.RemoteAddr().String()
+	s.logger.Info("accepted connection", zap.String("remote", remote))
+
+	rwc, tracer := s.TraceStream(conn)
+	stream := jsonrpc2.NewStream(rwc)
+	if err := s.Serve(ctx, stream, tracer); err != nil {
+		s.logger.Error("session error", zap.String("remote", remote), zap.Error(err))
+	}
+
+	s.logger.Info("connection closed", zap.String("remote", remote))
+}
+
+// lspHandler implements protocol.Server interface. One is created per
+// connection by Serve, so session, client, tracer, initialized, and
+// shuttingDown are all scoped to that single connection; only server
+// (logger, registry, configured trace level) is shared.
 type lspHandler struct {
 	server *Server
+
+	session *cache.Session
+	client  protocol.Client
+	tracer  *trace.Tracker
+
+	initialized  bool
+	shuttingDown bool
+}
+
+// fileContext resolves the view and parsed file for uri, and the capability
+// Handler registered for that file's Tekton kind.
+func (h *lspHandler) fileContext(uri protocol.DocumentURI) (handlers.Context, handlers.Handler, bool) {
+	view := h.session.ViewOf(uri)
+	pf, ok := view.Snapshot().File(uri)
+	if !ok {
+		return handlers.Context{}, nil, false
+	}
+
+	hctx := handlers.Context{View: view, File: pf}
+	return hctx, h.server.registry.For(pf.Kind), true
 }
 
 // Initialize handles the initialize request
@@ -57,11 +166,19 @@ func (h *lspHandler) Initialize(ctx context.Context, params *protocol.Initialize
 		zap.String("clientName", params.ClientInfo.Name),
 	)
 
-	if h.server.initialized {
+	if h.initialized {
 		return nil, fmt.Errorf("server already initialized")
 	}
 
-	h.server.initialized = true
+	h.initialized = true
+
+	if len(params.WorkspaceFolders) > 0 {
+		for _, folder := range params.WorkspaceFolders {
+			h.session.AddFolder(protocol.DocumentURI(folder.URI))
+		}
+	} else if params.RootURI != "" {
+		h.session.AddFolder(params.RootURI)
+	}
 
 	return &protocol.InitializeResult{
 		Capabilities: protocol.ServerCapabilities{
@@ -69,7 +186,26 @@ func (h *lspHandler) Initialize(ctx context.Context, params *protocol.Initialize
 				OpenClose: true,
 				Change:    protocol.TextDocumentSyncKindIncremental,
 			},
-			// More capabilities will be added in later tasks
+			CompletionProvider:     &protocol.CompletionOptions{},
+			HoverProvider:          true,
+			DefinitionProvider:     true,
+			ReferencesProvider:     true,
+			RenameProvider:         true,
+			DocumentSymbolProvider: true,
+			CodeActionProvider:     true,
+			FoldingRangeProvider:   true,
+			SemanticTokensProvider: &semanticTokensOptions{
+				Legend: protocol.SemanticTokensLegend{
+					TokenTypes: []protocol.SemanticTokenTypes{
+						protocol.SemanticTokenKeyword,
+						protocol.SemanticTokenProperty,
+						protocol.SemanticTokenString,
+						protocol.SemanticTokenParameter,
+					},
+					TokenModifiers: []protocol.SemanticTokenModifiers{},
+				},
+				Full: true,
+			},
 		},
 		ServerInfo: &protocol.ServerInfo{
 			Name:    "tekton-lsp",
@@ -78,33 +214,73 @@ func (h *lspHandler) Initialize(ctx context.Context, params *protocol.Initialize
 	}, nil
 }
 
+// semanticTokensOptions extends protocol.SemanticTokensOptions with the
+// legend and full-document-request fields the LSP spec puts on
+// semanticTokensProvider but go.lsp.dev/protocol's SemanticTokensOptions
+// doesn't expose; ServerCapabilities.SemanticTokensProvider is declared as
+// interface{} precisely so callers can plug a type like this one in.
+type semanticTokensOptions struct {
+	protocol.SemanticTokensOptions
+	Legend protocol.SemanticTokensLegend `json:"legend"`
+	Full   bool                          `json:"full"`
+}
+
 // Initialized handles the initialized notification
 func (h *lspHandler) Initialized(ctx context.Context, params *protocol.InitializedParams) error {
 	h.server.logger.Info("client confirmed initialization")
+
+	// Ask the client to notify us about YAML files changing on disk, so the
+	// workspace symbol index stays current for files we haven't opened.
+	if err := h.client.RegisterCapability(ctx, &protocol.RegistrationParams{
+		Registrations: []protocol.Registration{
+			{
+				ID:     "tekton-lsp-watch-yaml",
+				Method: "workspace/didChangeWatchedFiles",
+				RegisterOptions: protocol.DidChangeWatchedFilesRegistrationOptions{
+					Watchers: []protocol.FileSystemWatcher{
+						{GlobPattern: "**/*.{yaml,yml}"},
+					},
+				},
+			},
+		},
+	}); err != nil {
+		h.server.logger.Warn("client does not support watched-file registration", zap.Error(err))
+	}
+
 	return nil
 }
 
 // Shutdown handles the shutdown request
 func (h *lspHandler) Shutdown(ctx context.Context) error {
 	h.server.logger.Info("received shutdown request")
-	h.server.shuttingDown = true
+	h.shuttingDown = true
 	return nil
 }
 
 // Exit handles the exit notification
 func (h *lspHandler) Exit(ctx context.Context) error {
 	h.server.logger.Info("received exit notification")
-	if !h.server.shuttingDown {
+	if !h.shuttingDown {
 		h.server.logger.Warn("exit without shutdown")
 	}
 	return nil
 }
 
-// Stub implementations for required protocol.Server methods
-// These will be implemented in later tasks
+// Remaining protocol.Server methods. Most are no-ops: this server doesn't
+// have a kind-specific behavior for them (yet). CodeAction, Completion,
+// Definition, DocumentSymbol, FoldingRange, Hover, References, Rename, and
+// SemanticTokensFull below are real implementations, dispatched through
+// fileContext/handlers.Registry or pkg/cache directly. The text-sync
+// notifications (DidOpen, DidChange, DidSave, DidClose), DidChangeWatchedFiles
+// and DidChangeWorkspaceFolders feed the cache.Session, and SetTrace changes
+// this connection's trace level.
 
 func (h *lspHandler) CodeAction(ctx context.Context, params *protocol.CodeActionParams) ([]protocol.CodeAction, error) {
-	return nil, nil
+	hctx, handler, ok := h.fileContext(params.TextDocument.URI)
+	if !ok {
+		return nil, nil
+	}
+	return handler.CodeActions(ctx, hctx, params)
 }
 
 func (h *lspHandler) CodeLens(ctx context.Context, params *protocol.CodeLensParams) ([]protocol.CodeLens, error) {
@@ -124,7 +300,11 @@ func (h *lspHandler) ColorPresentation(ctx context.Context, params *protocol.Col
 }
 
 func (h *lspHandler) Completion(ctx context.Context, params *protocol.CompletionParams) (*protocol.CompletionList, error) {
-	return nil, nil
+	hctx, handler, ok := h.fileContext(params.TextDocument.URI)
+	if !ok {
+		return nil, nil
+	}
+	return handler.Completions(ctx, hctx, params)
 }
 
 func (h *lspHandler) CompletionResolve(ctx context.Context, params *protocol.CompletionItem) (*protocol.CompletionItem, error) {
@@ -136,10 +316,30 @@ func (h *lspHandler) Declaration(ctx context.Context, params *protocol.Declarati
 }
 
 func (h *lspHandler) Definition(ctx context.Context, params *protocol.DefinitionParams) ([]protocol.Location, error) {
-	return nil, nil
+	uri := params.TextDocument.URI
+	view := h.session.ViewOf(uri)
+
+	pf, ok := view.Snapshot().File(uri)
+	if !ok || pf.AST == nil {
+		return nil, nil
+	}
+
+	ref, ok := cache.RefAt(pf.AST, params.Position)
+	if !ok {
+		return nil, nil
+	}
+
+	var locations []protocol.Location
+	for _, kind := range ref.Kind.ReferentKinds() {
+		for _, sym := range view.Lookup(kind, ref.Name) {
+			locations = append(locations, protocol.Location{URI: sym.URI, Range: sym.NameRange})
+		}
+	}
+	return locations, nil
 }
 
 func (h *lspHandler) DidChange(ctx context.Context, params *protocol.DidChangeTextDocumentParams) error {
+	h.session.DidChange(params.TextDocument.URI, int32(params.TextDocument.Version), params.ContentChanges)
 	return nil
 }
 
@@ -148,10 +348,24 @@ func (h *lspHandler) DidChangeConfiguration(ctx context.Context, params *protoco
 }
 
 func (h *lspHandler) DidChangeWatchedFiles(ctx context.Context, params *protocol.DidChangeWatchedFilesParams) error {
+	changes := make([]protocol.FileEvent, 0, len(params.Changes))
+	for _, change := range params.Changes {
+		if change == nil {
+			continue
+		}
+		changes = append(changes, *change)
+	}
+	h.session.DidChangeWatchedFiles(changes)
 	return nil
 }
 
 func (h *lspHandler) DidChangeWorkspaceFolders(ctx context.Context, params *protocol.DidChangeWorkspaceFoldersParams) error {
+	for _, folder := range params.Event.Added {
+		h.session.AddFolder(protocol.DocumentURI(folder.URI))
+	}
+	for _, folder := range params.Event.Removed {
+		h.session.RemoveFolder(protocol.DocumentURI(folder.URI))
+	}
 	return nil
 }
 
@@ -168,14 +382,18 @@ func (h *lspHandler) DidRenameFiles(ctx context.Context, params *protocol.Rename
 }
 
 func (h *lspHandler) DidClose(ctx context.Context, params *protocol.DidCloseTextDocumentParams) error {
+	h.session.DidClose(params.TextDocument.URI)
 	return nil
 }
 
 func (h *lspHandler) DidOpen(ctx context.Context, params *protocol.DidOpenTextDocumentParams) error {
+	doc := params.TextDocument
+	h.session.DidOpen(doc.URI, string(doc.LanguageID), int32(doc.Version), doc.Text)
 	return nil
 }
 
 func (h *lspHandler) DidSave(ctx context.Context, params *protocol.DidSaveTextDocumentParams) error {
+	h.session.DidSave(params.TextDocument.URI)
 	return nil
 }
 
@@ -196,7 +414,11 @@ func (h *lspHandler) DocumentLinkResolve(ctx context.Context, params *protocol.D
 }
 
 func (h *lspHandler) DocumentSymbol(ctx context.Context, params *protocol.DocumentSymbolParams) ([]interface{}, error) {
-	return nil, nil
+	hctx, handler, ok := h.fileContext(params.TextDocument.URI)
+	if !ok {
+		return nil, nil
+	}
+	return handler.DocumentSymbols(ctx, hctx)
 }
 
 func (h *lspHandler) ExecuteCommand(ctx context.Context, params *protocol.ExecuteCommandParams) (interface{}, error) {
@@ -204,7 +426,11 @@ func (h *lspHandler) ExecuteCommand(ctx context.Context, params *protocol.Execut
 }
 
 func (h *lspHandler) FoldingRange(ctx context.Context, params *protocol.FoldingRangeParams) ([]protocol.FoldingRange, error) {
-	return nil, nil
+	hctx, handler, ok := h.fileContext(params.TextDocument.URI)
+	if !ok {
+		return nil, nil
+	}
+	return handler.FoldingRange(ctx, hctx)
 }
 
 func (h *lspHandler) FoldingRanges(ctx context.Context, params *protocol.FoldingRangeParams) ([]protocol.FoldingRange, error) {
@@ -216,7 +442,11 @@ func (h *lspHandler) Formatting(ctx context.Context, params *protocol.DocumentFo
 }
 
 func (h *lspHandler) Hover(ctx context.Context, params *protocol.HoverParams) (*protocol.Hover, error) {
-	return nil, nil
+	hctx, handler, ok := h.fileContext(params.TextDocument.URI)
+	if !ok {
+		return nil, nil
+	}
+	return handler.Hover(ctx, hctx, params)
 }
 
 func (h *lspHandler) Implementation(ctx context.Context, params *protocol.ImplementationParams) ([]protocol.Location, error) {
@@ -260,11 +490,90 @@ func (h *lspHandler) RangeFormatting(ctx context.Context, params *protocol.Docum
 }
 
 func (h *lspHandler) References(ctx context.Context, params *protocol.ReferenceParams) ([]protocol.Location, error) {
-	return nil, nil
+	uri := params.TextDocument.URI
+	view := h.session.ViewOf(uri)
+
+	pf, ok := view.Snapshot().File(uri)
+	if !ok || pf.AST == nil {
+		return nil, nil
+	}
+
+	var locations []protocol.Location
+
+	if sym, ok := cache.SymbolAt(pf, params.Position); ok {
+		if params.Context.IncludeDeclaration {
+			locations = append(locations, protocol.Location{URI: sym.URI, Range: sym.NameRange})
+		}
+		for _, refKind := range sym.Kind.RefKindsPointingHere() {
+			for _, occ := range view.RefsByName(refKind, sym.Name) {
+				locations = append(locations, protocol.Location{URI: occ.URI, Range: occ.Ref.NameRange})
+			}
+		}
+		return locations, nil
+	}
+
+	if ref, ok := cache.RefAt(pf.AST, params.Position); ok {
+		for _, occ := range view.RefsByName(ref.Kind, ref.Name) {
+			locations = append(locations, protocol.Location{URI: occ.URI, Range: occ.Ref.NameRange})
+		}
+		if params.Context.IncludeDeclaration {
+			for _, kind := range ref.Kind.ReferentKinds() {
+				for _, sym := range view.Lookup(kind, ref.Name) {
+					locations = append(locations, protocol.Location{URI: sym.URI, Range: sym.NameRange})
+				}
+			}
+		}
+	}
+
+	return locations, nil
 }
 
 func (h *lspHandler) Rename(ctx context.Context, params *protocol.RenameParams) (*protocol.WorkspaceEdit, error) {
-	return nil, nil
+	uri := params.TextDocument.URI
+	view := h.session.ViewOf(uri)
+
+	pf, ok := view.Snapshot().File(uri)
+	if !ok || pf.AST == nil {
+		return nil, nil
+	}
+
+	name, refKinds, defs, ok := renameTarget(view, pf, params.Position)
+	if !ok {
+		return nil, nil
+	}
+
+	edits := make(map[protocol.DocumentURI][]protocol.TextEdit)
+	for _, sym := range defs {
+		edits[sym.URI] = append(edits[sym.URI], protocol.TextEdit{Range: sym.NameRange, NewText: params.NewName})
+	}
+	for _, refKind := range refKinds {
+		for _, occ := range view.RefsByName(refKind, name) {
+			edits[occ.URI] = append(edits[occ.URI], protocol.TextEdit{Range: occ.Ref.NameRange, NewText: params.NewName})
+		}
+	}
+
+	return &protocol.WorkspaceEdit{Changes: edits}, nil
+}
+
+// renameTarget resolves the symbol name being renamed from the cursor
+// position, whichever side of the taskRef/pipelineRef relationship it sits
+// on: the resource's own metadata.name, or a reference to it. It returns
+// every definition that must be renamed alongside the name and the ref
+// kinds whose occurrences need updating.
+func renameTarget(view *cache.View, pf *cache.ParsedFile, pos protocol.Position) (name string, refKinds []cache.RefKind, defs []cache.Symbol, ok bool) {
+	if sym, found := cache.SymbolAt(pf, pos); found {
+		return sym.Name, sym.Kind.RefKindsPointingHere(), []cache.Symbol{sym}, true
+	}
+
+	if ref, found := cache.RefAt(pf.AST, pos); found {
+		var targets []cache.Symbol
+		for _, kind := range ref.Kind.ReferentKinds() {
+			targets = append(targets, view.Lookup(kind, ref.Name)...)
+		}
+		return ref.Name, []cache.RefKind{ref.Kind}, targets, true
+	}
+
+	return "", nil, nil, false
 }
 
 func (h *lspHandler) Request(ctx context.Context, method string, params interface{}) (interface{}, error) {
@@ -277,7 +586,11 @@ func (h *lspHandler) SelectionRange(ctx context.Context, params *protocol.Select
 }
 
 func (h *lspHandler) SemanticTokensFull(ctx context.Context, params *protocol.SemanticTokensParams) (*protocol.SemanticTokens, error) {
-	return nil, nil
+	hctx, handler, ok := h.fileContext(params.TextDocument.URI)
+	if !ok {
+		return nil, nil
+	}
+	return handler.SemanticTokens(ctx, hctx)
 }
 
 func (h *lspHandler) SemanticTokensFullDelta(ctx context.Context, params *protocol.SemanticTokensDeltaParams) (interface{}, error) {
@@ -293,6 +606,7 @@ func (h *lspHandler) SemanticTokensRefresh(ctx context.Context) error {
 }
 
 func (h *lspHandler) SetTrace(ctx context.Context, params *protocol.SetTraceParams) error {
+	h.tracer.SetLevel(trace.ParseLevel(string(params.Value)))
 	return nil
 }
 
@@ -0,0 +1,25 @@
+package trace
+
+import "regexp"
+
+// secretFields matches the common credential-bearing JSON field names that
+// show up in initialize options, workspace configuration, and the like:
+// "password": "...", "token": "...", etc. The replacement keeps the key and
+// quotes so the rest of the JSON still looks like JSON.
+var secretFields = regexp.MustCompile(`(?i)("(?:password|token|secret|api[_-]?key|authorization)"\s*:\s*")[^"]*(")`)
+
+// bearerTokens matches Authorization-header-style "Bearer <token>" values
+// that can appear inside a traced frame even outside a named JSON field.
+var bearerTokens = regexp.MustCompile(`(?i)(Bearer\s+)\S+`)
+
+const redacted = "${1}REDACTED${2}"
+
+// Redact returns a copy of data with anything that looks like a credential
+// replaced by a REDACTED placeholder, so verbose tracing is safe to leave on
+// in a shared log without leaking secrets sent through initializationOptions
+// or workspace/configuration.
+func Redact(data []byte) []byte {
+	out := secretFields.ReplaceAll(data, []byte(redacted))
+	out = bearerTokens.ReplaceAll(out, []byte("${1}REDACTED"))
+	return out
+}
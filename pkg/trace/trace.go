@@ -0,0 +1,169 @@
+// Package trace implements optional JSON-RPC wire tracing: reader/writer
+// shims (rSpy/wSpy, in the terminology other LSP servers use for the same
+// thing) that log every inbound/outbound chunk of bytes exchanged with a
+// client (one per Read or Write call, which need not line up with one
+// JSON-RPC message), and
+// a Tracker that lets the trace level be changed at runtime in response to
+// the standard LSP $/setTrace notification.
+package trace
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// Level is how much of the wire traffic gets logged.
+type Level int32
+
+const (
+	// Off logs nothing. The default.
+	Off Level = iota
+	// Messages logs one line per chunk read or written: direction and byte
+	// count.
+	Messages
+	// Verbose additionally logs the (redacted) frame content.
+	Verbose
+)
+
+// ParseLevel parses the TEKTON_LSP_TRACE env var / --trace flag value.
+// Anything other than "messages" or "verbose" (case-insensitive) is Off.
+func ParseLevel(s string) Level {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "messages":
+		return Messages
+	case "verbose":
+		return Verbose
+	default:
+		return Off
+	}
+}
+
+func (l Level) String() string {
+	switch l {
+	case Messages:
+		return "messages"
+	case Verbose:
+		return "verbose"
+	default:
+		return "off"
+	}
+}
+
+// Notifier forwards a trace line to the LSP client, typically via
+// window/logMessage, so trace output shows up in the editor's output
+// channel and not just the server's own log file.
+type Notifier func(message string)
+
+// Tracker holds the current trace level, changeable at runtime (by
+// $/setTrace), and logs frames at that level via zap, optionally also
+// forwarding them to the client through a Notifier.
+type Tracker struct {
+	logger *zap.Logger
+	level  atomic.Int32
+
+	mu       sync.Mutex
+	notifier Notifier
+}
+
+// NewTracker creates a Tracker starting at initial.
+func NewTracker(logger *zap.Logger, initial Level) *Tracker {
+	t := &Tracker{logger: logger}
+	t.level.Store(int32(initial))
+	return t
+}
+
+// Level returns the current trace level.
+func (t *Tracker) Level() Level {
+	return Level(t.level.Load())
+}
+
+// SetLevel changes the trace level, taking effect on the very next chunk.
+func (t *Tracker) SetLevel(l Level) {
+	t.logger.Info("trace level changed", zap.String("level", l.String()))
+	t.level.Store(int32(l))
+}
+
+// SetNotifier sets the callback used to forward trace lines to the LSP
+// client once one is available (the client dispatcher isn't constructed
+// until a connection is established, so this is wired up after NewTracker).
+func (t *Tracker) SetNotifier(n Notifier) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.notifier = n
+}
+
+// notify forwards message to the client, if a Notifier has been set.
+func (t *Tracker) notify(message string) {
+	t.mu.Lock()
+	n := t.notifier
+	t.mu.Unlock()
+	if n != nil {
+		n(message)
+	}
+}
+
+// Wrap returns a ReadWriteCloser that logs every chunk read from r and
+// written to w at the tracker's current level, closing via c.
+func (t *Tracker) Wrap(r io.Reader, w io.Writer, c io.Closer) io.ReadWriteCloser {
+	return struct {
+		io.Reader
+		io.Writer
+		io.Closer
+	}{
+		Reader: &rSpy{Reader: r, tracker: t},
+		Writer: &wSpy{Writer: w, tracker: t},
+		Closer: c,
+	}
+}
+
+// rSpy wraps an io.Reader, logging every chunk read from the client.
+type rSpy struct {
+	io.Reader
+	tracker *Tracker
+}
+
+func (s *rSpy) Read(p []byte) (int, error) {
+	n, err := s.Reader.Read(p)
+	if n > 0 {
+		s.tracker.log("recv", p[:n])
+	}
+	return n, err
+}
+
+// wSpy wraps an io.Writer, logging every chunk written to the client.
+type wSpy struct {
+	io.Writer
+	tracker *Tracker
+}
+
+func (s *wSpy) Write(p []byte) (int, error) {
+	n, err := s.Writer.Write(p)
+	if n > 0 {
+		s.tracker.log("send", p[:n])
+	}
+	return n, err
+}
+
+// log emits one chunk at the tracker's current level, redacting anything
+// that looks like a credential before it's written to the log or forwarded
+// to the client via window/logMessage.
+func (t *Tracker) log(direction string, data []byte) {
+	switch t.Level() {
+	case Messages:
+		t.logger.Debug("jsonrpc2 frame", zap.String("direction", direction), zap.Int("bytes", len(data)))
+		t.notify(fmt.Sprintf("%s: %d bytes", direction, len(data)))
+	case Verbose:
+		redacted := Redact(data)
+		t.logger.Debug("jsonrpc2 frame",
+			zap.String("direction", direction),
+			zap.Int("bytes", len(data)),
+			zap.ByteString("content", redacted),
+		)
+		t.notify(fmt.Sprintf("%s: %s", direction, redacted))
+	}
+}